@@ -1,10 +1,11 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
-	"strconv"
+	"slices"
 	"strings"
 
 	"github.com/andersonjoseph/drill/internal/components/breakpoints"
@@ -12,31 +13,251 @@ import (
 	"github.com/andersonjoseph/drill/internal/components/localvariables"
 	"github.com/andersonjoseph/drill/internal/components/output"
 	"github.com/andersonjoseph/drill/internal/components/sourcecode"
+	"github.com/andersonjoseph/drill/internal/components/watch"
 	"github.com/andersonjoseph/drill/internal/components/window"
+	"github.com/andersonjoseph/drill/internal/config"
 	"github.com/andersonjoseph/drill/internal/debugger"
+	"github.com/andersonjoseph/drill/internal/paths"
+	"github.com/andersonjoseph/drill/internal/store"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-func parseEntryBreakpoint(bp string) (string, int, error) {
-	breakpointAttrs := strings.Split(bp, ":")
+type locationsFlag []string
 
-	filename := breakpointAttrs[0]
-	line, err := strconv.Atoi(breakpointAttrs[1])
+func (l *locationsFlag) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *locationsFlag) Set(location string) error {
+	*l = append(*l, location)
+	return nil
+}
+
+// createBreakpoints creates the breakpoints given with -b, skipping the
+// locations restored from a previous session already.
+func createBreakpoints(d *debugger.Debugger, locations []string) error {
+	existing, err := d.Breakpoints()
+	if err != nil {
+		return err
+	}
+
+	restored := make(map[string]bool, len(existing))
+	for _, bp := range existing {
+		restored[store.Location(bp.Filename, bp.Line)] = true
+	}
+
+	for _, location := range locations {
+		lines, err := d.LocationLines(location)
+		if err != nil {
+			return err
+		}
+
+		missing := slices.ContainsFunc(lines, func(line string) bool { return !restored[line] })
+		if !missing {
+			continue
+		}
+
+		if _, err := d.CreateBreakpointsAt(location); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restoreBreakpoints recreates the breakpoints saved by a previous session,
+// following their lines if the source changed since, and reports the ones that
+// were moved or could not be restored in the output window.
+func restoreBreakpoints(d *debugger.Debugger, projectRoot string) {
+	var report []string
+
+	project, err := store.Load(projectRoot)
+	if err != nil {
+		report = append(report, err.Error())
+	}
+
+	// dependencies refer to the location the breakpoints were saved at
+	restored := make(map[string]debugger.Breakpoint)
+	ids := make([]int, len(project.Breakpoints))
+
+	var moved, orphaned []string
+	for i := range project.Breakpoints {
+		saved := &project.Breakpoints[i]
+
+		var bp debugger.Breakpoint
+		line, err := relocateBreakpoint(*saved)
+		if err == nil {
+			bp, err = restoreBreakpoint(d, *saved, line)
+		}
 
-	return filename, line, err
+		if err != nil {
+			saved.Orphaned = true
+			orphaned = append(orphaned, fmt.Sprintf("  %s:%d: %v", saved.Filename, saved.Line, err))
+			continue
+		}
+
+		restored[store.Location(saved.Filename, saved.Line)] = bp
+		ids[i] = bp.ID
+
+		if line != saved.Line {
+			moved = append(moved, fmt.Sprintf("  %s:%d -> %d", saved.Filename, saved.Line, line))
+			saved.Line = line
+		}
+		saved.Orphaned = false
+	}
+
+	var dependenciesChanged bool
+	for i := range project.Breakpoints {
+		saved := &project.Breakpoints[i]
+		if saved.DependsOn == "" || ids[i] == 0 {
+			continue
+		}
+
+		prerequisite, ok := restored[saved.DependsOn]
+		if !ok {
+			report = append(report, fmt.Sprintf("breakpoint %s:%d no longer depends on %s, it could not be restored", saved.Filename, saved.Line, saved.DependsOn))
+			saved.DependsOn = ""
+			dependenciesChanged = true
+			continue
+		}
+
+		if _, err := d.SetBreakpointDependency(ids[i], prerequisite.ID); err != nil {
+			report = append(report, err.Error())
+			continue
+		}
+
+		if location := store.Location(prerequisite.Filename, prerequisite.Line); location != saved.DependsOn {
+			saved.DependsOn = location
+			dependenciesChanged = true
+		}
+	}
+
+	if len(moved) > 0 {
+		report = append(report, fmt.Sprintf("moved %d breakpoint(s) to follow source changes:\n%s", len(moved), strings.Join(moved, "\n")))
+	}
+
+	if len(orphaned) > 0 {
+		report = append(report, fmt.Sprintf("could not restore %d breakpoint(s), they are kept as orphaned:\n%s", len(orphaned), strings.Join(orphaned, "\n")))
+	}
+
+	if len(moved) > 0 || len(orphaned) > 0 || dependenciesChanged {
+		if err := store.Save(projectRoot, project); err != nil {
+			report = append(report, err.Error())
+		}
+	}
+
+	if len(report) == 0 {
+		return
+	}
+
+	// nothing reads the output before the program starts
+	go func() {
+		d.Output <- debugger.Output{Source: debugger.SourceCommand, Content: strings.Join(report, "\n")}
+	}()
+}
+
+func relocateBreakpoint(saved store.Breakpoint) (int, error) {
+	lines, err := store.ReadLines(saved.Filename)
+	if err != nil {
+		return 0, fmt.Errorf("error reading source file: %w", err)
+	}
+
+	line, ok := store.Relocate(lines, saved)
+	if !ok {
+		return 0, errors.New("line not found in the current source")
+	}
+
+	return line, nil
+}
+
+// restoreBreakpoint creates the breakpoint with the settings it was saved
+// with. It is cleared again if any of them fails, the saved entry is kept as
+// an orphan instead of being doubled by a live breakpoint.
+func restoreBreakpoint(d *debugger.Debugger, saved store.Breakpoint, line int) (debugger.Breakpoint, error) {
+	bp, err := d.CreateBreakpoint(saved.Filename, line)
+	if err != nil {
+		return bp, err
+	}
+
+	if err := restoreSettings(d, bp, saved); err != nil {
+		if clearErr := d.ClearBreakpoint(bp.ID); clearErr != nil {
+			return bp, errors.Join(err, clearErr)
+		}
+		return bp, err
+	}
+
+	return bp, nil
+}
+
+func restoreSettings(d *debugger.Debugger, bp debugger.Breakpoint, saved store.Breakpoint) error {
+	if saved.Name != "" {
+		if _, err := d.AddAliasToBreakpoint(bp.ID, saved.Name); err != nil {
+			return err
+		}
+	}
+
+	if saved.Condition != "" {
+		if _, err := d.AddConditionToBreakpoint(bp.ID, saved.Condition); err != nil {
+			return err
+		}
+	}
+
+	if saved.HitCondition != "" || saved.HitCondPerG {
+		if _, err := d.SetBreakpointHitCondition(bp.ID, saved.HitCondition, saved.HitCondPerG); err != nil {
+			return err
+		}
+	}
+
+	if saved.LogMessage != "" {
+		if _, err := d.SetBreakpointLogMessage(bp.ID, saved.LogMessage); err != nil {
+			return err
+		}
+	}
+
+	if saved.Group != "" {
+		if _, err := d.SetBreakpointGroup(bp.ID, saved.Group); err != nil {
+			return err
+		}
+	}
+
+	if saved.CaptureOnHit {
+		if _, err := d.SetBreakpointCapture(bp.ID, true); err != nil {
+			return err
+		}
+	}
+
+	if saved.Temporary {
+		if _, err := d.SetBreakpointTemporary(bp.ID, true); err != nil {
+			return err
+		}
+	}
+
+	if saved.Disabled {
+		return d.ToggleBreakpoint(bp.ID)
+	}
+
+	return nil
 }
 
 func main() {
-	var bp string
+	var bps locationsFlag
 	var command string
 	var filename string
 
 	flag.StringVar(&filename, "f", "", "filename")
-	flag.StringVar(&bp, "b", "", "create a breakpoint")
+	flag.Var(&bps, "b", "create a breakpoint at a location (file.go:42, main.handler, /regex/...), can be repeated")
 	flag.StringVar(&command, "c", "debug", "dlv command to run")
 
 	flag.Parse()
 
+	projectRoot := paths.GetProjectRoot()
+
+	cfg, err := config.Load(projectRoot)
+	if err != nil {
+		fmt.Println("Error loading config:", err)
+		os.Exit(1)
+	}
+
 	debugger, err := debugger.New(command, filename)
 	if err != nil {
 		fmt.Println("Error creating debugger", err)
@@ -44,9 +265,13 @@ func main() {
 	}
 	defer debugger.Close()
 
+	debugger.SetLoadConfig(cfg.Load)
+	debugger.SetPrinters(cfg.Printers)
+
 	localvariablesWindow := window.New(1, "Local Variables", localvariables.New(1, debugger))
 	breakpointsWindow := window.New(2, "Breakpoints", breakpoints.New(2, debugger))
 	callstackWindow := window.New(3, "Callstack", callstack.New(3, debugger))
+	watchWindow := window.New(6, "Watch", watch.New(6, debugger))
 
 	sourcecodeWindow := window.New(4, "Source Code", sourcecode.New(4, "Source Code", debugger))
 	outputWindow := window.New(5, "Output", output.New(5, "Output", debugger))
@@ -57,23 +282,19 @@ func main() {
 			localvariablesWindow,
 			breakpointsWindow,
 			callstackWindow,
+			watchWindow,
 		},
 		sourceCode: sourcecodeWindow,
 		output:     outputWindow,
 	}
 
-	if bp != "" {
-		filename, line, err := parseEntryBreakpoint(bp)
-		if err != nil {
-			fmt.Println("Error parsing breakpoint:", err)
-			os.Exit(1)
-		}
+	if projectRoot != "" {
+		restoreBreakpoints(debugger, projectRoot)
+	}
 
-		_, err = debugger.CreateBreakpoint(filename, line)
-		if err != nil {
-			fmt.Println("Error parsing breakpoint:", err)
-			os.Exit(1)
-		}
+	if err := createBreakpoints(debugger, bps); err != nil {
+		fmt.Println("Error creating breakpoint:", err)
+		os.Exit(1)
 	}
 
 	if _, err := tea.NewProgram(m, tea.WithAltScreen()).Run(); err != nil {
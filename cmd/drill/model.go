@@ -90,9 +90,7 @@ func (m model) View() string {
 				lipgloss.Top,
 				lipgloss.JoinVertical(
 					lipgloss.Top,
-					m.sidebar[0].View(),
-					m.sidebar[1].View(),
-					m.sidebar[2].View(),
+					m.sidebarViews()...,
 				),
 				lipgloss.JoinVertical(
 					lipgloss.Top,
@@ -105,6 +103,15 @@ func (m model) View() string {
 	)
 }
 
+func (m model) sidebarViews() []string {
+	views := make([]string, len(m.sidebar))
+	for i := range m.sidebar {
+		views[i] = m.sidebar[i].View()
+	}
+
+	return views
+}
+
 func (m *model) handleResize(msg tea.WindowSizeMsg) tea.Cmd {
 	const (
 		sidebarRatio      = 0.3
@@ -124,7 +131,7 @@ func (m *model) handleResize(msg tea.WindowSizeMsg) tea.Cmd {
 	}
 
 	sidebarAvailableHeight := msg.Height - 2
-	sidebarComponentHeight := sidebarAvailableHeight / 3
+	sidebarComponentHeight := sidebarAvailableHeight / len(m.sidebar)
 
 	// --- Main Panel Calculations (Source Code + Output) ---
 	mainPanelWidth := msg.Width - sidebarWidth - mainPanelHPadding
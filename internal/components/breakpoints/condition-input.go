@@ -1,6 +1,9 @@
 package breakpoints
 
 import (
+	"strings"
+	"unicode"
+
 	"github.com/andersonjoseph/drill/internal/components"
 	"github.com/andersonjoseph/drill/internal/messages"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -17,11 +20,14 @@ type conditionInputModel struct {
 	isFocused bool
 	textInput textinput.Model
 	width     int
+	// names are the variables visible at the breakpoint, offered as completions
+	names []string
 }
 
 func newConditionInputModel(id int) conditionInputModel {
 	ti := textinput.New()
 	ti.Placeholder = "condition"
+	ti.ShowSuggestions = true
 
 	return conditionInputModel{
 		id:        id,
@@ -68,6 +74,7 @@ func (m conditionInputModel) Update(msg tea.Msg) (conditionInputModel, tea.Cmd)
 			)
 		}
 		m.textInput, cmd = m.textInput.Update(msg)
+		m.updateSuggestions()
 		return m, cmd
 
 	case tea.WindowSizeMsg:
@@ -90,4 +97,34 @@ func (m *conditionInputModel) setFocus(f bool) {
 
 func (m *conditionInputModel) setContent(c string) {
 	m.textInput.SetValue(c)
+	m.textInput.CursorEnd()
+	m.updateSuggestions()
+}
+
+func (m *conditionInputModel) setNames(names []string) {
+	m.names = names
+}
+
+// updateSuggestions completes the identifier being typed at the end of the
+// input, tab accepts the suggestion.
+func (m *conditionInputModel) updateSuggestions() {
+	value := m.textInput.Value()
+	start := strings.LastIndexFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) + 1
+
+	word := value[start:]
+	if word == "" || (start > 0 && value[start-1] == '.') {
+		m.textInput.SetSuggestions(nil)
+		return
+	}
+
+	var suggestions []string
+	for _, name := range m.names {
+		if strings.HasPrefix(name, word) && name != word {
+			suggestions = append(suggestions, value[:start]+name)
+		}
+	}
+
+	m.textInput.SetSuggestions(suggestions)
 }
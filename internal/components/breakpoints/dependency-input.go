@@ -0,0 +1,96 @@
+package breakpoints
+
+import (
+	"github.com/andersonjoseph/drill/internal/components"
+	"github.com/andersonjoseph/drill/internal/messages"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var dependencyInputStyle lipgloss.Style = lipgloss.NewStyle().
+	Foreground(components.ColorWhite).
+	Border(lipgloss.NormalBorder()).
+	BorderForeground(components.ColorYellow)
+
+type messageNewDependency string
+
+type dependencyInputModel struct {
+	id        int
+	isFocused bool
+	textInput textinput.Model
+	width     int
+}
+
+func newDependencyInputModel(id int) dependencyInputModel {
+	ti := textinput.New()
+	ti.Placeholder = "stop only after breakpoint (name or id)"
+
+	return dependencyInputModel{
+		id:        id,
+		textInput: ti,
+	}
+}
+
+func (m dependencyInputModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m dependencyInputModel) Update(msg tea.Msg) (dependencyInputModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		var cmd tea.Cmd
+		if msg.String() == "esc" {
+			m.setFocus(false)
+			m.textInput.SetValue("")
+
+			return m, tea.Batch(
+				func() tea.Msg {
+					return messages.TextInputFocused(false)
+				},
+				func() tea.Msg {
+					return messages.WindowFocused(m.id)
+				},
+			)
+		}
+
+		if msg.String() == "enter" {
+			m.setFocus(false)
+			content := m.textInput.Value()
+			m.textInput.SetValue("")
+			return m, tea.Batch(
+				func() tea.Msg {
+					return messages.TextInputFocused(false)
+				},
+				func() tea.Msg {
+					return messages.WindowFocused(m.id)
+				},
+				func() tea.Msg {
+					return messageNewDependency(content)
+				},
+			)
+		}
+		m.textInput, cmd = m.textInput.Update(msg)
+		return m, cmd
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.textInput.Width = m.width - 3
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m dependencyInputModel) View() string {
+	return dependencyInputStyle.Render(m.textInput.View())
+}
+
+func (m *dependencyInputModel) setFocus(f bool) {
+	m.isFocused = f
+	m.textInput.Focus()
+}
+
+func (m *dependencyInputModel) setContent(c string) {
+	m.textInput.SetValue(c)
+}
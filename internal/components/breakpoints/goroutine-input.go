@@ -0,0 +1,96 @@
+package breakpoints
+
+import (
+	"github.com/andersonjoseph/drill/internal/components"
+	"github.com/andersonjoseph/drill/internal/messages"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var goroutineInputStyle lipgloss.Style = lipgloss.NewStyle().
+	Foreground(components.ColorWhite).
+	Border(lipgloss.NormalBorder()).
+	BorderForeground(components.ColorYellow)
+
+type messageNewGoroutine string
+
+type goroutineInputModel struct {
+	id        int
+	isFocused bool
+	textInput textinput.Model
+	width     int
+}
+
+func newGoroutineInputModel(id int) goroutineInputModel {
+	ti := textinput.New()
+	ti.Placeholder = "goroutine id (empty: any goroutine)"
+
+	return goroutineInputModel{
+		id:        id,
+		textInput: ti,
+	}
+}
+
+func (m goroutineInputModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m goroutineInputModel) Update(msg tea.Msg) (goroutineInputModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		var cmd tea.Cmd
+		if msg.String() == "esc" {
+			m.setFocus(false)
+			m.textInput.SetValue("")
+
+			return m, tea.Batch(
+				func() tea.Msg {
+					return messages.TextInputFocused(false)
+				},
+				func() tea.Msg {
+					return messages.WindowFocused(m.id)
+				},
+			)
+		}
+
+		if msg.String() == "enter" {
+			m.setFocus(false)
+			content := m.textInput.Value()
+			m.textInput.SetValue("")
+			return m, tea.Batch(
+				func() tea.Msg {
+					return messages.TextInputFocused(false)
+				},
+				func() tea.Msg {
+					return messages.WindowFocused(m.id)
+				},
+				func() tea.Msg {
+					return messageNewGoroutine(content)
+				},
+			)
+		}
+		m.textInput, cmd = m.textInput.Update(msg)
+		return m, cmd
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.textInput.Width = m.width - 3
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m goroutineInputModel) View() string {
+	return goroutineInputStyle.Render(m.textInput.View())
+}
+
+func (m *goroutineInputModel) setFocus(f bool) {
+	m.isFocused = f
+	m.textInput.Focus()
+}
+
+func (m *goroutineInputModel) setContent(c string) {
+	m.textInput.SetValue(c)
+}
@@ -0,0 +1,96 @@
+package breakpoints
+
+import (
+	"github.com/andersonjoseph/drill/internal/components"
+	"github.com/andersonjoseph/drill/internal/messages"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var groupInputStyle lipgloss.Style = lipgloss.NewStyle().
+	Foreground(components.ColorWhite).
+	Border(lipgloss.NormalBorder()).
+	BorderForeground(components.ColorYellow)
+
+type messageNewGroup string
+
+type groupInputModel struct {
+	id        int
+	isFocused bool
+	textInput textinput.Model
+	width     int
+}
+
+func newGroupInputModel(id int) groupInputModel {
+	ti := textinput.New()
+	ti.Placeholder = "group"
+
+	return groupInputModel{
+		id:        id,
+		textInput: ti,
+	}
+}
+
+func (m groupInputModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m groupInputModel) Update(msg tea.Msg) (groupInputModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		var cmd tea.Cmd
+		if msg.String() == "esc" {
+			m.setFocus(false)
+			m.textInput.SetValue("")
+
+			return m, tea.Batch(
+				func() tea.Msg {
+					return messages.TextInputFocused(false)
+				},
+				func() tea.Msg {
+					return messages.WindowFocused(m.id)
+				},
+			)
+		}
+
+		if msg.String() == "enter" {
+			m.setFocus(false)
+			content := m.textInput.Value()
+			m.textInput.SetValue("")
+			return m, tea.Batch(
+				func() tea.Msg {
+					return messages.TextInputFocused(false)
+				},
+				func() tea.Msg {
+					return messages.WindowFocused(m.id)
+				},
+				func() tea.Msg {
+					return messageNewGroup(content)
+				},
+			)
+		}
+		m.textInput, cmd = m.textInput.Update(msg)
+		return m, cmd
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.textInput.Width = m.width - 3
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m groupInputModel) View() string {
+	return groupInputStyle.Render(m.textInput.View())
+}
+
+func (m *groupInputModel) setFocus(f bool) {
+	m.isFocused = f
+	m.textInput.Focus()
+}
+
+func (m *groupInputModel) setContent(c string) {
+	m.textInput.SetValue(c)
+}
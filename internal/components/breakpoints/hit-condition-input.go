@@ -0,0 +1,93 @@
+package breakpoints
+
+import (
+	"github.com/andersonjoseph/drill/internal/components"
+	"github.com/andersonjoseph/drill/internal/messages"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var hitConditionInputStyle lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorWhite).Border(lipgloss.NormalBorder()).BorderForeground(components.ColorYellow)
+
+type messageNewHitCondition string
+
+type hitConditionInputModel struct {
+	id        int
+	isFocused bool
+	textInput textinput.Model
+	width     int
+}
+
+func newHitConditionInputModel(id int) hitConditionInputModel {
+	ti := textinput.New()
+	ti.Placeholder = "hit condition, e.g. > 10 or % 5 == 0"
+
+	return hitConditionInputModel{
+		id:        id,
+		textInput: ti,
+	}
+}
+
+func (m hitConditionInputModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m hitConditionInputModel) Update(msg tea.Msg) (hitConditionInputModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		var cmd tea.Cmd
+		if msg.String() == "esc" {
+			m.setFocus(false)
+			m.textInput.SetValue("")
+
+			return m, tea.Batch(
+				func() tea.Msg {
+					return messages.TextInputFocused(false)
+				},
+				func() tea.Msg {
+					return messages.WindowFocused(m.id)
+				},
+			)
+		}
+
+		if msg.String() == "enter" {
+			m.setFocus(false)
+			content := m.textInput.Value()
+			m.textInput.SetValue("")
+			return m, tea.Batch(
+				func() tea.Msg {
+					return messages.TextInputFocused(false)
+				},
+				func() tea.Msg {
+					return messages.WindowFocused(m.id)
+				},
+				func() tea.Msg {
+					return messageNewHitCondition(content)
+				},
+			)
+		}
+		m.textInput, cmd = m.textInput.Update(msg)
+		return m, cmd
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.textInput.Width = m.width - 3
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m hitConditionInputModel) View() string {
+	return hitConditionInputStyle.Render(m.textInput.View())
+}
+
+func (m *hitConditionInputModel) setFocus(f bool) {
+	m.isFocused = f
+	m.textInput.Focus()
+}
+
+func (m *hitConditionInputModel) setContent(c string) {
+	m.textInput.SetValue(c)
+}
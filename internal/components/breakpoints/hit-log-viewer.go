@@ -0,0 +1,131 @@
+package breakpoints
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andersonjoseph/drill/internal/components"
+	"github.com/andersonjoseph/drill/internal/debugger"
+	"github.com/andersonjoseph/drill/internal/messages"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+const hitLogHintString = "esc: close, j: down, k: up"
+
+var (
+	hitHeaderStyle = lipgloss.NewStyle().Foreground(components.ColorPurple).Bold(true)
+	hitLabelStyle  = lipgloss.NewStyle().Foreground(components.ColorYellow)
+	hitValueStyle  = lipgloss.NewStyle().Foreground(components.ColorWhite)
+	hitFrameStyle  = lipgloss.NewStyle().Foreground(components.ColorGrey)
+	noHitsStyle    = lipgloss.NewStyle().Foreground(components.ColorGrey)
+)
+
+type hitLogViewerModel struct {
+	id       int
+	isOpen   bool
+	viewport viewport.Model
+	content  string
+}
+
+func newHitLogViewer(id int) hitLogViewerModel {
+	return hitLogViewerModel{
+		id:       id,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (m hitLogViewerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m hitLogViewerModel) Update(msg tea.Msg) (hitLogViewerModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.viewport.Height = msg.Height
+		m.viewport.Width = msg.Width
+		m.viewport.SetContent(wordwrap.String(m.content, m.viewport.Width))
+		return m, nil
+
+	case tea.KeyMsg:
+		if !m.isOpen {
+			return m, nil
+		}
+
+		if msg.String() == "esc" {
+			m.isOpen = false
+			return m, tea.Batch(
+				func() tea.Msg {
+					return messages.WindowFocused(m.id)
+				}, func() tea.Msg {
+					return messages.WindowTitleChanged{WindowID: m.id, Title: "Breakpoints"}
+				},
+			)
+		}
+
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m hitLogViewerModel) View() string {
+	return m.viewport.View()
+}
+
+func (m *hitLogViewerModel) open(hits []debugger.BreakpointHit) {
+	m.isOpen = true
+	m.content = renderHits(hits)
+
+	m.viewport.SetContent(wordwrap.String(m.content, m.viewport.Width))
+	m.viewport.GotoTop()
+}
+
+func renderHits(hits []debugger.BreakpointHit) string {
+	if len(hits) == 0 {
+		return noHitsStyle.Render("no hits recorded yet")
+	}
+
+	sb := strings.Builder{}
+	for i, hit := range hits {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+
+		sb.WriteString(hitHeaderStyle.Render(fmt.Sprintf(
+			"#%d goroutine %d at %s",
+			i+1,
+			hit.GoroutineID,
+			hit.Time.Format("15:04:05.000"),
+		)))
+
+		writeVariables(&sb, "args", hit.Arguments)
+		writeVariables(&sb, "locals", hit.Locals)
+
+		if len(hit.Stack) > 0 {
+			sb.WriteString("\n" + hitLabelStyle.Render("stack:"))
+			for _, frame := range hit.Stack {
+				sb.WriteString("\n  " + hitValueStyle.Render(frame.FunctionName))
+				sb.WriteString("\n    " + hitFrameStyle.Render(fmt.Sprintf("%s:%d", frame.Filename, frame.Line)))
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+func writeVariables(sb *strings.Builder, label string, vars []debugger.Variable) {
+	if len(vars) == 0 {
+		return
+	}
+
+	sb.WriteString("\n" + hitLabelStyle.Render(label+":"))
+	for _, v := range vars {
+		sb.WriteString(fmt.Sprintf("\n  %s = %s", v.Name, hitValueStyle.Render(v.Value)))
+	}
+}
@@ -4,12 +4,17 @@ import (
 	"cmp"
 	"fmt"
 	"io"
+	"maps"
+	"path/filepath"
 	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/andersonjoseph/drill/internal/components"
 	"github.com/andersonjoseph/drill/internal/debugger"
 	"github.com/andersonjoseph/drill/internal/messages"
 	"github.com/andersonjoseph/drill/internal/paths"
+	"github.com/andersonjoseph/drill/internal/store"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/paginator"
 	tea "github.com/charmbracelet/bubbletea"
@@ -18,7 +23,13 @@ import (
 
 const (
 	breakpointSymbol = "⏺"
-	hintString       = "t: toggle, d: delete, enter: select, c: condition, r: alias, j: down, k: up"
+	watchpointSymbol = "◉"
+	logpointSymbol   = "◆"
+	temporarySymbol  = "○"
+	dependentSymbol  = "◐"
+	orphanSymbol     = "?"
+	hintString       = "t: toggle, d: delete, enter: select, c: condition, /: filter, ?: more keys, j: down, k: up"
+	fullHintString   = "t: toggle, d: delete, enter: select, c: condition, h: hit condition, H: per goroutine hits, r: alias, g: group, G: goroutine, l: log message, s: capture on hit, v: view hits, o: one-shot, a: stop after, /: filter (group:name, file:name), E/D/X: enable/disable/delete listed, ?: fewer keys, j: down, k: up"
 )
 
 var (
@@ -33,6 +44,24 @@ var (
 	indicatorEnabled  = lipgloss.NewStyle().Foreground(components.ColorRed).Render(breakpointSymbol, " ")
 	indicatorDisabled = lipgloss.NewStyle().Foreground(components.ColorGrey).Render(breakpointSymbol, " ")
 	conditionStyle    = lipgloss.NewStyle().Foreground(components.ColorYellow)
+	hitCountStyle     = lipgloss.NewStyle().Foreground(components.ColorWhite)
+	captureStyle      = lipgloss.NewStyle().Foreground(components.ColorGreen)
+	groupStyle        = lipgloss.NewStyle().Foreground(components.ColorPurple)
+
+	watchpointIndicatorEnabled  = lipgloss.NewStyle().Foreground(components.ColorOrange).Render(watchpointSymbol, " ")
+	watchpointIndicatorDisabled = lipgloss.NewStyle().Foreground(components.ColorGrey).Render(watchpointSymbol, " ")
+	watchTypeStyle              = lipgloss.NewStyle().Foreground(components.ColorOrange)
+
+	logpointIndicatorEnabled  = lipgloss.NewStyle().Foreground(components.ColorYellow).Render(logpointSymbol, " ")
+	logpointIndicatorDisabled = lipgloss.NewStyle().Foreground(components.ColorGrey).Render(logpointSymbol, " ")
+
+	temporaryIndicatorEnabled  = lipgloss.NewStyle().Foreground(components.ColorRed).Render(temporarySymbol, " ")
+	temporaryIndicatorDisabled = lipgloss.NewStyle().Foreground(components.ColorGrey).Render(temporarySymbol, " ")
+	dependentIndicatorEnabled  = lipgloss.NewStyle().Foreground(components.ColorRed).Render(dependentSymbol, " ")
+	dependentIndicatorDisabled = lipgloss.NewStyle().Foreground(components.ColorGrey).Render(dependentSymbol, " ")
+
+	orphanIndicator = lipgloss.NewStyle().Foreground(components.ColorGrey).Render(orphanSymbol, " ")
+	orphanStyle     = lipgloss.NewStyle().Foreground(components.ColorOrange)
 
 	listItemStyle = lipgloss.NewStyle()
 )
@@ -46,16 +75,36 @@ type Model struct {
 	list            list.Model
 	debugger        *debugger.Debugger
 	conditionInput  conditionInputModel
+	hitCondInput    hitConditionInputModel
 	aliasInput      aliasInputModel
+	logMessageInput logMessageInputModel
+	dependencyInput dependencyInputModel
+	groupInput      groupInputModel
+	goroutineInput  goroutineInputModel
+	hitLogViewer    hitLogViewerModel
+	projectRoot     string
 	idToBreakpoints map[int]debugger.Breakpoint
+	watchValues     map[int]string
+	hitLogs         map[int][]debugger.BreakpointHit
+	// orphans are saved breakpoints whose line could not be found in the
+	// current source, they are kept until the user deletes them.
+	orphans []store.Breakpoint
+	// showAllKeys makes the hint list every key instead of the main ones
+	showAllKeys bool
 }
 
 func New(id int, d *debugger.Debugger) Model {
 	l := list.New([]list.Item{}, listDelegate{}, 0, 0)
 	l.SetShowHelp(false)
-	l.SetShowFilter(false)
+	l.SetShowFilter(true)
+	l.Filter = filterBreakpoints
 	l.SetShowTitle(false)
 	l.SetShowStatusBar(false)
+	// the window's own keys would be shadowed by the list's paging ones
+	l.KeyMap.PrevPage.SetKeys("left", "pgup", "b", "u")
+	l.KeyMap.NextPage.SetKeys("right", "pgdown", "f")
+	l.KeyMap.GoToStart.SetKeys("home")
+	l.KeyMap.GoToEnd.SetKeys("end")
 	l.Styles.PaginationStyle = paginatorStyleDefault
 	l.Styles.NoItems = lipgloss.NewStyle().Width(0)
 
@@ -76,8 +125,17 @@ func New(id int, d *debugger.Debugger) Model {
 		list:            l,
 		debugger:        d,
 		conditionInput:  newConditionInputModel(id),
+		hitCondInput:    newHitConditionInputModel(id),
 		aliasInput:      newAliasInputModel(id),
+		logMessageInput: newLogMessageInputModel(id),
+		dependencyInput: newDependencyInputModel(id),
+		groupInput:      newGroupInputModel(id),
+		goroutineInput:  newGoroutineInputModel(id),
+		hitLogViewer:    newHitLogViewer(id),
+		projectRoot:     paths.GetProjectRoot(),
 		idToBreakpoints: make(map[int]debugger.Breakpoint),
+		watchValues:     make(map[int]string),
+		hitLogs:         make(map[int][]debugger.BreakpointHit),
 	}
 }
 
@@ -96,9 +154,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.list.Styles.PaginationStyle = paginatorStyleFocused
 		}
 
-		return m, func() tea.Msg {
-			return messages.UpdatedHint(hintString)
-		}
+		return m, m.hintCmd()
 
 	case tea.WindowSizeMsg:
 		m.height = msg.Height
@@ -108,10 +164,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.list.Styles.NoItems = noItemsStyle.Width(msg.Width)
 
 		m.conditionInput, _ = m.conditionInput.Update(tea.WindowSizeMsg{Width: m.width})
+		m.hitCondInput, _ = m.hitCondInput.Update(tea.WindowSizeMsg{Width: m.width})
 		m.aliasInput, _ = m.aliasInput.Update(tea.WindowSizeMsg{Width: m.width})
+		m.logMessageInput, _ = m.logMessageInput.Update(tea.WindowSizeMsg{Width: m.width})
+		m.dependencyInput, _ = m.dependencyInput.Update(tea.WindowSizeMsg{Width: m.width})
+		m.groupInput, _ = m.groupInput.Update(tea.WindowSizeMsg{Width: m.width})
+		m.goroutineInput, _ = m.goroutineInput.Update(tea.WindowSizeMsg{Width: m.width})
+		m.hitLogViewer, _ = m.hitLogViewer.Update(msg)
+		return m, nil
+
+	case messages.RefreshContent:
+		if err := m.loadOrphans(); err != nil {
+			return m, messages.ErrorCmd(err)
+		}
+		if err := m.syncBreakpoints(); err != nil {
+			return m, messages.ErrorCmd(err)
+		}
 		return m, nil
 
-	case messages.RefreshContent, messages.DebuggerRestarted:
+	case messages.DebuggerRestarted, messages.DebuggerStepped:
 		if err := m.syncBreakpoints(); err != nil {
 			return m, messages.ErrorCmd(err)
 		}
@@ -124,8 +195,45 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.idToBreakpoints[msg.ID] = bp
 
-		m.list.SetItems(breakpointsToListItems(m.idToBreakpoints))
-		return m, nil
+		if bp.WatchExpr != "" {
+			v, err := m.debugger.WatchpointValue(bp.ID)
+			if err != nil {
+				return m, messages.ErrorCmd(err)
+			}
+			m.watchValues[bp.ID] = v.Value
+		}
+
+		m.setItems()
+		return m, m.saveBreakpoints()
+
+	case messages.DebuggerBreakpointHit:
+		var cmds []tea.Cmd
+		bp := msg.Breakpoint
+
+		if bp.CaptureOnHit {
+			m.hitLogs[bp.ID] = append(m.hitLogs[bp.ID], debugger.BreakpointHit(msg))
+		}
+
+		if bp.WatchExpr != "" && len(msg.Variables) > 0 {
+			cmds = append(cmds, m.watchpointHit(bp, msg.Variables[0]))
+		}
+
+		dependents, err := m.debugger.EnableDependentBreakpoints(bp.ID)
+		if err != nil {
+			cmds = append(cmds, messages.ErrorCmd(err))
+		}
+		for _, dependent := range dependents {
+			cmds = append(cmds, messages.DebuggerBreakpointToggledCmd(dependent.ID, dependent.Filename, dependent.Line))
+		}
+
+		if bp.Temporary {
+			if err := m.debugger.ClearBreakpoint(bp.ID); err != nil {
+				return m, tea.Batch(append(cmds, messages.ErrorCmd(err))...)
+			}
+			cmds = append(cmds, messages.DebuggerBreakpointClearedCmd(bp.ID, bp.Filename, bp.Line))
+		}
+
+		return m, tea.Batch(cmds...)
 
 	case messages.DebuggerBreakpointToggled:
 		bp := m.idToBreakpoints[msg.ID]
@@ -133,18 +241,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		bp.Disabled = !bp.Disabled
 		m.idToBreakpoints[msg.ID] = bp
 
-		m.list.SetItems(breakpointsToListItems(m.idToBreakpoints))
-		return m, nil
+		m.setItems()
+		return m, m.saveBreakpoints()
 
 	case messages.DebuggerBreakpointCleared:
 		delete(m.idToBreakpoints, msg.ID)
+		delete(m.watchValues, msg.ID)
+		delete(m.hitLogs, msg.ID)
 
-		m.list.SetItems(breakpointsToListItems(m.idToBreakpoints))
-		return m, nil
+		m.setItems()
+		return m, m.saveBreakpoints()
 
 	case messageNewCondition:
 		item := m.list.SelectedItem().(listItem)
 
+		// keep the breakpoint unchanged if Delve can't evaluate the condition
+		// there, names the source doesn't show are only warned about
+		var warning string
+		if cond := strings.TrimSpace(string(msg)); cond != "" {
+			var err error
+			warning, err = m.debugger.ValidateCondition(item.breakpoint.ID, cond)
+			if err != nil {
+				return m, messages.ErrorCmd(err)
+			}
+		}
+
 		bp, err := m.debugger.AddConditionToBreakpoint(item.breakpoint.ID, string(msg))
 		if err != nil {
 			return m, messages.ErrorCmd(err)
@@ -152,8 +273,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		m.idToBreakpoints[bp.ID] = bp
 
-		m.list.SetItems(breakpointsToListItems(m.idToBreakpoints))
-		return m, nil
+		m.setItems()
+		if warning != "" {
+			return m, tea.Batch(m.saveBreakpoints(), messages.OutputCmd(m.debugger.Output, warning))
+		}
+		return m, m.saveBreakpoints()
+
+	case messageNewHitCondition:
+		item := m.list.SelectedItem().(listItem)
+
+		bp, err := m.debugger.SetBreakpointHitCondition(item.breakpoint.ID, string(msg), item.breakpoint.HitCondPerG)
+		if err != nil {
+			return m, messages.ErrorCmd(err)
+		}
+
+		m.idToBreakpoints[bp.ID] = bp
+		m.setItems()
+		return m, m.saveBreakpoints()
 
 	case messageNewAlias:
 		item := m.list.SelectedItem().(listItem)
@@ -164,15 +300,89 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		m.idToBreakpoints[bp.ID] = bp
-		m.list.SetItems(breakpointsToListItems(m.idToBreakpoints))
+		m.setItems()
+		return m, m.saveBreakpoints()
+
+	case messageNewLogMessage:
+		item := m.list.SelectedItem().(listItem)
+
+		bp, err := m.debugger.SetBreakpointLogMessage(item.breakpoint.ID, string(msg))
+		if err != nil {
+			return m, messages.ErrorCmd(err)
+		}
+
+		m.idToBreakpoints[bp.ID] = bp
+		m.setItems()
+		return m, tea.Batch(
+			messages.DebuggerBreakpointAmendedCmd(bp.ID, bp.Filename, bp.Line),
+			m.saveBreakpoints(),
+		)
+
+	case messageNewGroup:
+		item := m.list.SelectedItem().(listItem)
+
+		bp, err := m.debugger.SetBreakpointGroup(item.breakpoint.ID, string(msg))
+		if err != nil {
+			return m, messages.ErrorCmd(err)
+		}
+
+		m.idToBreakpoints[bp.ID] = bp
+		m.setItems()
+		return m, m.saveBreakpoints()
+
+	case messageNewGoroutine:
+		item := m.list.SelectedItem().(listItem)
+
+		var goroutineID int64
+		if value := strings.TrimSpace(string(msg)); value != "" {
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || id <= 0 {
+				return m, messages.ErrorCmd(fmt.Errorf("error setting breakpoint goroutine: invalid goroutine id %q", value))
+			}
+			goroutineID = id
+		}
+
+		bp, err := m.debugger.SetBreakpointGoroutine(item.breakpoint.ID, goroutineID)
+		if err != nil {
+			return m, messages.ErrorCmd(err)
+		}
+
+		m.idToBreakpoints[bp.ID] = bp
+		m.setItems()
 		return m, nil
 
+	case list.FilterMatchesMsg:
+		var cmd tea.Cmd
+		m.list, cmd = m.list.Update(msg)
+		return m, cmd
+
+	case messageNewDependency:
+		item := m.list.SelectedItem().(listItem)
+
+		var dependsOn int
+		if name := strings.TrimSpace(string(msg)); name != "" {
+			prerequisite, ok := m.findBreakpoint(name)
+			if !ok {
+				return m, messages.ErrorCmd(fmt.Errorf("error setting breakpoint dependency: breakpoint %q not found", name))
+			}
+			dependsOn = prerequisite.ID
+		}
+
+		bp, err := m.debugger.SetBreakpointDependency(item.breakpoint.ID, dependsOn)
+		if err != nil {
+			return m, messages.ErrorCmd(err)
+		}
+
+		m.idToBreakpoints[bp.ID] = bp
+		m.setItems()
+		return m, tea.Batch(messages.DebuggerBreakpointAmendedCmd(bp.ID, bp.Filename, bp.Line), m.saveBreakpoints())
+
 	case messages.DebuggerBreakpointSelected:
 		if msg.FromWindowID == m.ID {
 			return m, nil
 		}
 
-		for i, item := range m.list.Items() {
+		for i, item := range m.list.VisibleItems() {
 			item := item.(listItem)
 			if item.breakpoint.ID == int(msg.ID) {
 				m.list.Select(i)
@@ -191,15 +401,70 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+		if m.hitCondInput.isFocused {
+			m.hitCondInput, cmd = m.hitCondInput.Update(msg)
+			return m, cmd
+		}
+
 		if m.aliasInput.isFocused {
 			m.aliasInput, cmd = m.aliasInput.Update(msg)
 			return m, cmd
 		}
 
+		if m.logMessageInput.isFocused {
+			m.logMessageInput, cmd = m.logMessageInput.Update(msg)
+			return m, cmd
+		}
+
+		if m.dependencyInput.isFocused {
+			m.dependencyInput, cmd = m.dependencyInput.Update(msg)
+			return m, cmd
+		}
+
+		if m.groupInput.isFocused {
+			m.groupInput, cmd = m.groupInput.Update(msg)
+			return m, cmd
+		}
+
+		if m.goroutineInput.isFocused {
+			m.goroutineInput, cmd = m.goroutineInput.Update(msg)
+			return m, cmd
+		}
+
+		if m.hitLogViewer.isOpen {
+			if !m.IsFocused {
+				return m, nil
+			}
+			m.hitLogViewer, cmd = m.hitLogViewer.Update(msg)
+			return m, cmd
+		}
+
 		if !m.IsFocused {
 			return m, nil
 		}
 
+		if m.list.FilterState() == list.Filtering {
+			return m, m.updateList(msg)
+		}
+
+		if item, ok := m.list.SelectedItem().(listItem); ok && item.orphan != nil {
+			switch msg.String() {
+			case "d":
+				m.orphans = slices.DeleteFunc(m.orphans, func(bp store.Breakpoint) bool {
+					return bp == *item.orphan
+				})
+				m.setItems()
+				return m, m.saveBreakpoints()
+			case "t", "c", "h", "H", "s", "o", "a", "v", "r", "l", "g", "G":
+				return m, messages.ErrorCmd(fmt.Errorf("breakpoint %s:%d is orphaned: its line could not be found in the current source", item.orphan.Filename, item.orphan.Line))
+			}
+		}
+
+		if msg.String() == "?" {
+			m.showAllKeys = !m.showAllKeys
+			return m, m.hintCmd()
+		}
+
 		if msg.String() == "t" {
 			bp, err := m.toggleBreakpoint()
 			if err != nil {
@@ -209,6 +474,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, messages.DebuggerBreakpointToggledCmd(bp.ID, bp.Filename, bp.Line)
 		}
 
+		if msg.String() == "E" || msg.String() == "D" {
+			return m, m.setListedBreakpointsDisabled(msg.String() == "D")
+		}
+
+		if msg.String() == "X" {
+			return m, m.clearListedBreakpoints()
+		}
+
 		if msg.String() == "d" {
 			bp, err := m.clearBreakpoint()
 			if err != nil {
@@ -224,6 +497,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			item := m.list.SelectedItem().(listItem)
 
+			// completions are a nicety, the input works without them
+			names, _ := m.debugger.VisibleNames(item.breakpoint.ID)
+			m.conditionInput.setNames(names)
+
 			m.conditionInput.setFocus(true)
 			m.conditionInput.setContent(item.breakpoint.Condition)
 			return m, func() tea.Msg {
@@ -231,6 +508,102 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		if msg.String() == "h" {
+			if m.list.SelectedItem() == nil {
+				return m, nil
+			}
+			item := m.list.SelectedItem().(listItem)
+
+			m.hitCondInput.setFocus(true)
+			m.hitCondInput.setContent(item.breakpoint.HitCondition)
+			return m, func() tea.Msg {
+				return messages.TextInputFocused(true)
+			}
+		}
+
+		if msg.String() == "H" {
+			if m.list.SelectedItem() == nil {
+				return m, nil
+			}
+			item := m.list.SelectedItem().(listItem)
+
+			bp, err := m.debugger.SetBreakpointHitCondition(item.breakpoint.ID, item.breakpoint.HitCondition, !item.breakpoint.HitCondPerG)
+			if err != nil {
+				return m, messages.ErrorCmd(err)
+			}
+
+			m.idToBreakpoints[bp.ID] = bp
+			m.setItems()
+			return m, m.saveBreakpoints()
+		}
+
+		if msg.String() == "s" {
+			if m.list.SelectedItem() == nil {
+				return m, nil
+			}
+			item := m.list.SelectedItem().(listItem)
+
+			bp, err := m.debugger.SetBreakpointCapture(item.breakpoint.ID, !item.breakpoint.CaptureOnHit)
+			if err != nil {
+				return m, messages.ErrorCmd(err)
+			}
+
+			m.idToBreakpoints[bp.ID] = bp
+			m.setItems()
+			return m, m.saveBreakpoints()
+		}
+
+		if msg.String() == "o" {
+			if m.list.SelectedItem() == nil {
+				return m, nil
+			}
+			item := m.list.SelectedItem().(listItem)
+
+			bp, err := m.debugger.SetBreakpointTemporary(item.breakpoint.ID, !item.breakpoint.Temporary)
+			if err != nil {
+				return m, messages.ErrorCmd(err)
+			}
+
+			m.idToBreakpoints[bp.ID] = bp
+			m.setItems()
+			return m, tea.Batch(messages.DebuggerBreakpointAmendedCmd(bp.ID, bp.Filename, bp.Line), m.saveBreakpoints())
+		}
+
+		if msg.String() == "a" {
+			if m.list.SelectedItem() == nil {
+				return m, nil
+			}
+			item := m.list.SelectedItem().(listItem)
+
+			var content string
+			if prerequisite, ok := m.idToBreakpoints[item.breakpoint.DependsOn]; ok {
+				content = prerequisite.Name
+			}
+
+			m.dependencyInput.setFocus(true)
+			m.dependencyInput.setContent(content)
+			return m, func() tea.Msg {
+				return messages.TextInputFocused(true)
+			}
+		}
+
+		if msg.String() == "v" {
+			if m.list.SelectedItem() == nil {
+				return m, nil
+			}
+			item := m.list.SelectedItem().(listItem)
+
+			m.hitLogViewer.open(m.hitLogs[item.breakpoint.ID])
+			return m, tea.Batch(
+				func() tea.Msg {
+					return messages.UpdatedHint(hitLogHintString)
+				},
+				func() tea.Msg {
+					return messages.WindowTitleChanged{WindowID: m.ID, Title: fmt.Sprintf("Hits of %s", item.breakpoint.Name)}
+				},
+			)
+		}
+
 		if msg.String() == "r" {
 			if m.list.SelectedItem() == nil {
 				return m, nil
@@ -245,6 +618,56 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		if msg.String() == "g" {
+			if m.list.SelectedItem() == nil {
+				return m, nil
+			}
+
+			item := m.list.SelectedItem().(listItem)
+
+			m.groupInput.setFocus(true)
+			m.groupInput.setContent(item.breakpoint.Group)
+			return m, func() tea.Msg {
+				return messages.TextInputFocused(true)
+			}
+		}
+
+		if msg.String() == "G" {
+			if m.list.SelectedItem() == nil {
+				return m, nil
+			}
+
+			item := m.list.SelectedItem().(listItem)
+
+			// suggest the goroutine the program is stopped on
+			var content string
+			if item.breakpoint.GoroutineID != 0 {
+				content = strconv.FormatInt(item.breakpoint.GoroutineID, 10)
+			} else if id, err := m.debugger.CurrentGoroutineID(); err == nil {
+				content = strconv.FormatInt(id, 10)
+			}
+
+			m.goroutineInput.setFocus(true)
+			m.goroutineInput.setContent(content)
+			return m, func() tea.Msg {
+				return messages.TextInputFocused(true)
+			}
+		}
+
+		if msg.String() == "l" {
+			if m.list.SelectedItem() == nil {
+				return m, nil
+			}
+
+			item := m.list.SelectedItem().(listItem)
+
+			m.logMessageInput.setFocus(true)
+			m.logMessageInput.setContent(item.breakpoint.LogMessage)
+			return m, func() tea.Msg {
+				return messages.TextInputFocused(true)
+			}
+		}
+
 		if msg.String() == "enter" {
 			if m.list.SelectedItem() == nil {
 				return m, nil
@@ -259,8 +682,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			)
 		}
 
-		m.list, cmd = m.list.Update(msg)
-		return m, cmd
+		return m, m.updateList(msg)
 	}
 
 	return m, nil
@@ -270,13 +692,124 @@ func (m Model) View() string {
 	if m.conditionInput.isFocused {
 		return m.conditionInput.View()
 	}
+	if m.hitCondInput.isFocused {
+		return m.hitCondInput.View()
+	}
 	if m.aliasInput.isFocused {
 		return m.aliasInput.View()
 	}
+	if m.logMessageInput.isFocused {
+		return m.logMessageInput.View()
+	}
+	if m.dependencyInput.isFocused {
+		return m.dependencyInput.View()
+	}
+	if m.groupInput.isFocused {
+		return m.groupInput.View()
+	}
+	if m.goroutineInput.isFocused {
+		return m.goroutineInput.View()
+	}
+	if m.hitLogViewer.isOpen {
+		return m.hitLogViewer.View()
+	}
 
 	return m.list.View()
 }
 
+// updateList passes msg to the list and tells the rest of the UI when the
+// filter box opens, closes or changes.
+func (m *Model) updateList(msg tea.Msg) tea.Cmd {
+	wasFiltering := m.list.FilterState() == list.Filtering
+	oldFilter := m.list.FilterValue()
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+
+	cmds := []tea.Cmd{cmd}
+
+	isFiltering := m.list.FilterState() == list.Filtering
+	if wasFiltering != isFiltering {
+		cmds = append(cmds, func() tea.Msg {
+			return messages.TextInputFocused(isFiltering)
+		})
+	}
+
+	if filter := m.list.FilterValue(); filter != oldFilter || wasFiltering != isFiltering {
+		title := m.title
+		if filter != "" && !isFiltering {
+			title = fmt.Sprintf("%s [%s]", m.title, filter)
+		}
+
+		cmds = append(cmds, func() tea.Msg {
+			return messages.WindowTitleChanged{WindowID: m.ID, Title: title}
+		})
+	}
+
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) setItems() {
+	cmd := m.list.SetItems(breakpointsToListItems(m.idToBreakpoints, m.orphans))
+	if cmd != nil {
+		// filter right away so the listed breakpoints never lag behind
+		m.list, _ = m.list.Update(cmd())
+	}
+}
+
+// listedBreakpoints returns the breakpoints that pass the current filter.
+func (m Model) listedBreakpoints() []debugger.Breakpoint {
+	var bps []debugger.Breakpoint
+	for _, item := range m.list.VisibleItems() {
+		if item := item.(listItem); item.orphan == nil {
+			bps = append(bps, item.breakpoint)
+		}
+	}
+
+	return bps
+}
+
+func (m Model) setListedBreakpointsDisabled(disabled bool) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, bp := range m.listedBreakpoints() {
+		if bp.Disabled == disabled {
+			continue
+		}
+
+		if err := m.debugger.ToggleBreakpoint(bp.ID); err != nil {
+			return tea.Batch(append(cmds, messages.ErrorCmd(err))...)
+		}
+		cmds = append(cmds, messages.DebuggerBreakpointToggledCmd(bp.ID, bp.Filename, bp.Line))
+	}
+
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) clearListedBreakpoints() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, bp := range m.listedBreakpoints() {
+		if err := m.debugger.ClearBreakpoint(bp.ID); err != nil {
+			return tea.Batch(append(cmds, messages.ErrorCmd(err))...)
+		}
+		cmds = append(cmds, messages.DebuggerBreakpointClearedCmd(bp.ID, bp.Filename, bp.Line))
+	}
+
+	// listed orphans go too, they only live in the saved state. The items
+	// point into m.orphans, so they are copied before it is modified.
+	listed := make(map[store.Breakpoint]bool)
+	for _, item := range m.list.VisibleItems() {
+		if item := item.(listItem); item.orphan != nil {
+			listed[*item.orphan] = true
+		}
+	}
+	m.orphans = slices.DeleteFunc(m.orphans, func(bp store.Breakpoint) bool {
+		return listed[bp]
+	})
+	m.setItems()
+
+	return tea.Batch(append(cmds, m.saveBreakpoints())...)
+}
+
 func (m *Model) syncBreakpoints() error {
 	bps, err := m.debugger.Breakpoints()
 	if err != nil {
@@ -290,10 +823,142 @@ func (m *Model) syncBreakpoints() error {
 		}
 	}
 
-	m.list.SetItems(breakpointsToListItems(m.idToBreakpoints))
+	m.setItems()
+	return nil
+}
+
+func (m *Model) loadOrphans() error {
+	if m.projectRoot == "" {
+		return nil
+	}
+
+	project, err := store.Load(m.projectRoot)
+	if err != nil {
+		return err
+	}
+
+	m.orphans = nil
+	for _, bp := range project.Breakpoints {
+		if bp.Orphaned {
+			m.orphans = append(m.orphans, bp)
+		}
+	}
+
+	return nil
+}
+
+func (m Model) saveBreakpoints() tea.Cmd {
+	if m.projectRoot == "" {
+		return nil
+	}
+
+	bps, err := m.debugger.Breakpoints()
+	if err != nil {
+		return messages.ErrorCmd(fmt.Errorf("error saving breakpoints: %w", err))
+	}
+
+	locations := make(map[int]string, len(bps))
+	for _, bp := range bps {
+		locations[bp.ID] = store.Location(bp.Filename, bp.Line)
+	}
+
+	saved := make([]store.Breakpoint, 0, len(bps)+len(m.orphans))
+	sources := make(map[string][]string)
+	for _, bp := range bps {
+		// watchpoints are bound to a stack frame, they can't outlive the session
+		if bp.WatchExpr != "" {
+			continue
+		}
+
+		stored := toStoredBreakpoint(bp)
+		if bp.DependsOn != 0 {
+			stored.DependsOn = locations[bp.DependsOn]
+		}
+
+		lines, ok := sources[bp.Filename]
+		if !ok {
+			// without a fingerprint the breakpoint is restored at the same line
+			lines, _ = store.ReadLines(bp.Filename)
+			sources[bp.Filename] = lines
+		}
+		stored.Fingerprint = store.NewFingerprint(lines, bp.Line)
+
+		saved = append(saved, stored)
+	}
+	saved = append(saved, m.orphans...)
+
+	err = store.Update(m.projectRoot, func(p *store.Project) {
+		p.Breakpoints = saved
+	})
+	if err != nil {
+		return messages.ErrorCmd(fmt.Errorf("error saving breakpoints: %w", err))
+	}
+
 	return nil
 }
 
+func toStoredBreakpoint(bp debugger.Breakpoint) store.Breakpoint {
+	name := bp.Name
+	if name == fmt.Sprintf("%s:%d", bp.Filename, bp.Line) {
+		name = ""
+	}
+
+	return store.Breakpoint{
+		Filename:     bp.Filename,
+		Line:         bp.Line,
+		Name:         name,
+		Condition:    bp.Condition,
+		HitCondition: bp.HitCondition,
+		HitCondPerG:  bp.HitCondPerG,
+		// a dependent breakpoint is disabled by its dependency, which is
+		// restored with it
+		Disabled:   bp.Disabled && bp.DependsOn == 0,
+		LogMessage: bp.LogMessage,
+		Group:      bp.Group,
+		Temporary:  bp.Temporary,
+		// the captured hits themselves only live for the session
+		CaptureOnHit: bp.CaptureOnHit,
+	}
+}
+
+func (m *Model) watchpointHit(bp debugger.Breakpoint, v debugger.Variable) tea.Cmd {
+	oldValue := m.watchValues[bp.ID]
+	newValue := v.Value
+	m.watchValues[bp.ID] = newValue
+
+	if oldValue == newValue {
+		return messages.OutputCmd(m.debugger.Output, fmt.Sprintf("watchpoint %s read: %s", bp.WatchExpr, newValue))
+	}
+
+	return messages.OutputCmd(m.debugger.Output, fmt.Sprintf("watchpoint %s changed: %s → %s", bp.WatchExpr, oldValue, newValue))
+}
+
+func (m *Model) findBreakpoint(nameOrID string) (debugger.Breakpoint, bool) {
+	if id, err := strconv.Atoi(nameOrID); err == nil {
+		bp, ok := m.idToBreakpoints[id]
+		return bp, ok
+	}
+
+	for _, bp := range m.idToBreakpoints {
+		if bp.Name == nameOrID {
+			return bp, true
+		}
+	}
+
+	return debugger.Breakpoint{}, false
+}
+
+func (m Model) hintCmd() tea.Cmd {
+	hint := hintString
+	if m.showAllKeys {
+		hint = fullHintString
+	}
+
+	return func() tea.Msg {
+		return messages.UpdatedHint(hint)
+	}
+}
+
 func (m *Model) toggleBreakpoint() (debugger.Breakpoint, error) {
 	i := m.list.SelectedItem()
 	if i == nil {
@@ -338,22 +1003,117 @@ func (d listDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd { return nil }
 
 type listItem struct {
 	breakpoint debugger.Breakpoint
+	orphan     *store.Breakpoint
 	isFocused  bool
 }
 
-func (i listItem) FilterValue() string { return "" }
+// FilterValue packs the fields filterBreakpoints matches against.
+func (i listItem) FilterValue() string {
+	if i.orphan != nil {
+		name := i.orphan.Name
+		if name == "" {
+			name = fmt.Sprintf("%s:%d", i.orphan.Filename, i.orphan.Line)
+		}
+		return strings.Join([]string{name, i.orphan.Filename, i.orphan.Group}, "\n")
+	}
+
+	return strings.Join([]string{i.breakpoint.Name, i.breakpoint.Filename, i.breakpoint.Group}, "\n")
+}
+
+// filterBreakpoints matches "group:name" against the group, "file:name"
+// against the filename and anything else against the name, file and group.
+func filterBreakpoints(term string, targets []string) []list.Rank {
+	term = strings.ToLower(strings.TrimSpace(term))
+
+	var ranks []list.Rank
+	for i, target := range targets {
+		name, filename, group := splitFilterValue(strings.ToLower(target))
+
+		var match bool
+		switch {
+		case strings.HasPrefix(term, "group:"):
+			match = group == strings.TrimSpace(strings.TrimPrefix(term, "group:"))
+		case strings.HasPrefix(term, "file:"):
+			match = strings.Contains(filepath.Base(filename), strings.TrimSpace(strings.TrimPrefix(term, "file:")))
+		default:
+			match = strings.Contains(name, term) || strings.Contains(filename, term) || strings.Contains(group, term)
+		}
+
+		if match {
+			ranks = append(ranks, list.Rank{Index: i})
+		}
+	}
+
+	return ranks
+}
+
+func splitFilterValue(value string) (name, filename, group string) {
+	fields := strings.SplitN(value, "\n", 3)
+	for len(fields) < 3 {
+		fields = append(fields, "")
+	}
+
+	return fields[0], fields[1], fields[2]
+}
 
 func (i listItem) Render(width int) string {
+	if i.orphan != nil {
+		return i.renderOrphan(width)
+	}
+
 	var indicator string
-	if i.breakpoint.Disabled {
+	switch {
+	case i.breakpoint.WatchExpr != "" && i.breakpoint.Disabled:
+		indicator = watchpointIndicatorDisabled
+	case i.breakpoint.WatchExpr != "":
+		indicator = watchpointIndicatorEnabled
+	case i.breakpoint.LogMessage != "" && i.breakpoint.Disabled:
+		indicator = logpointIndicatorDisabled
+	case i.breakpoint.LogMessage != "":
+		indicator = logpointIndicatorEnabled
+	case i.breakpoint.DependsOn != 0 && i.breakpoint.Disabled:
+		indicator = dependentIndicatorDisabled
+	case i.breakpoint.DependsOn != 0:
+		indicator = dependentIndicatorEnabled
+	case i.breakpoint.Temporary && i.breakpoint.Disabled:
+		indicator = temporaryIndicatorDisabled
+	case i.breakpoint.Temporary:
+		indicator = temporaryIndicatorEnabled
+	case i.breakpoint.Disabled:
 		indicator = indicatorDisabled
-	} else {
+	default:
 		indicator = indicatorEnabled
 	}
 
 	var item string
+	if i.breakpoint.Group != "" {
+		item = groupStyle.Render(fmt.Sprintf("[%s] ", i.breakpoint.Group))
+	}
+	if i.breakpoint.WatchExpr != "" {
+		item += watchTypeStyle.Render(fmt.Sprintf("[%s] ", i.breakpoint.WatchType))
+	}
 	if i.breakpoint.Condition != "" {
-		item = conditionStyle.Render("when", i.breakpoint.Condition, "")
+		item += conditionStyle.Render("when", i.breakpoint.Condition, "")
+	}
+
+	if i.breakpoint.GoroutineID != 0 {
+		item += conditionStyle.Render(fmt.Sprintf("g%d ", i.breakpoint.GoroutineID))
+	}
+	if i.breakpoint.CaptureOnHit {
+		item += captureStyle.Render("rec ")
+	}
+	if i.breakpoint.Temporary {
+		item += conditionStyle.Render("once ")
+	}
+	if i.breakpoint.DependsOn != 0 {
+		item += conditionStyle.Render(fmt.Sprintf("after #%d ", i.breakpoint.DependsOn))
+	}
+	if i.breakpoint.HitCondition != "" {
+		hitCond := "hit " + i.breakpoint.HitCondition
+		if i.breakpoint.HitCondPerG {
+			hitCond = "hit per goroutine " + i.breakpoint.HitCondition
+		}
+		item += conditionStyle.Render(hitCond, "")
 	}
 
 	var style lipgloss.Style
@@ -363,7 +1123,10 @@ func (i listItem) Render(width int) string {
 		style = breakpointStyleDefault
 	}
 
-	item += style.Render(paths.Trunc(i.breakpoint.Name, width-len(item)-5))
+	hits := hitCountStyle.Render(hitCounts(i.breakpoint, i.isFocused))
+
+	item += style.Render(paths.Trunc(i.breakpoint.Name, width-lipgloss.Width(item)-lipgloss.Width(hits)-5))
+	item += hits
 
 	breakpoint :=
 		lipgloss.JoinHorizontal(lipgloss.Top, indicator, item)
@@ -373,8 +1136,48 @@ func (i listItem) Render(width int) string {
 		Render(breakpoint)
 }
 
-func breakpointsToListItems(bps map[int]debugger.Breakpoint) []list.Item {
-	items := make([]list.Item, 0, len(bps))
+func (i listItem) renderOrphan(width int) string {
+	item := orphanStyle.Render("orphaned ")
+
+	name := i.orphan.Name
+	if name == "" {
+		name = fmt.Sprintf("%s:%d", i.orphan.Filename, i.orphan.Line)
+	}
+
+	style := breakpointStyleDefault
+	if i.isFocused {
+		style = breakpointStyleFocused
+	}
+	item += style.Render(paths.Trunc(name, width-lipgloss.Width(item)-5))
+
+	return listItemStyle.
+		Width(width).
+		Render(lipgloss.JoinHorizontal(lipgloss.Top, orphanIndicator, item))
+}
+
+// hitCounts shows the total number of hits, broken down per goroutine when
+// the item is focused.
+func hitCounts(bp debugger.Breakpoint, detailed bool) string {
+	if bp.TotalHitCount == 0 {
+		return ""
+	}
+
+	total := fmt.Sprintf(" ×%d", bp.TotalHitCount)
+	if !detailed || len(bp.HitCounts) == 0 {
+		return total
+	}
+
+	goroutineIDs := slices.Sorted(maps.Keys(bp.HitCounts))
+	perGoroutine := make([]string, len(goroutineIDs))
+	for i, id := range goroutineIDs {
+		perGoroutine[i] = fmt.Sprintf("g%d:%d", id, bp.HitCounts[id])
+	}
+
+	return fmt.Sprintf("%s (%s)", total, strings.Join(perGoroutine, " "))
+}
+
+func breakpointsToListItems(bps map[int]debugger.Breakpoint, orphans []store.Breakpoint) []list.Item {
+	items := make([]list.Item, 0, len(bps)+len(orphans))
 	for _, bp := range bps {
 		items = append(items, listItem{breakpoint: bp})
 	}
@@ -383,5 +1186,10 @@ func breakpointsToListItems(bps map[int]debugger.Breakpoint) []list.Item {
 		return cmp.Compare(a.(listItem).breakpoint.ID, b.(listItem).breakpoint.ID)
 	})
 
+	// orphans go last, they are not known to the debugger
+	for i := range orphans {
+		items = append(items, listItem{orphan: &orphans[i]})
+	}
+
 	return items
 }
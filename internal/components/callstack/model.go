@@ -16,7 +16,13 @@ import (
 )
 
 const (
-	hintString = "enter: select, j: down, k: up"
+	hintString = "enter: select, m: load more frames, h: collapse/expand library frames, j: down, k: up"
+
+	defaultStackDepth = 50
+	stackDepthStep    = 50
+
+	maxAncestors  = 10
+	ancestorDepth = 20
 )
 
 var (
@@ -28,6 +34,14 @@ var (
 	frameStyleSelected lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorGreen)
 	frameStyleDefault  lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorGrey)
 
+	functionNameStyle lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorPurple)
+	argumentsStyle    lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorWhite)
+	inlinedStyle      lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorYellow)
+	deferStyle        lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorOrange)
+	moreFramesStyle   lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorGrey)
+	hiddenFramesStyle lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorGrey).Italic(true)
+	ancestryStyle     lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorYellow)
+
 	listItemStyle lipgloss.Style = lipgloss.NewStyle()
 )
 
@@ -41,6 +55,15 @@ type Model struct {
 	debugger     *debugger.Debugger
 	openFilename string
 	lineNumber   int
+	depth        int
+	stack        []debugger.StackFrame
+	ancestors    []debugger.Ancestor
+	showAncestry bool
+	// ancestorsErr is the last error loading the ancestors, logged once
+	ancestorsErr string
+
+	collapseLibraryFrames bool
+	expandedGroups        map[int]bool
 }
 
 func New(id int, debugger *debugger.Debugger) Model {
@@ -49,6 +72,8 @@ func New(id int, debugger *debugger.Debugger) Model {
 	l.SetShowFilter(false)
 	l.SetShowTitle(false)
 	l.SetShowStatusBar(false)
+	// h collapses the library frames
+	l.KeyMap.PrevPage.SetKeys("left", "pgup", "b", "u")
 	l.Styles.PaginationStyle = paginatorStyleDefault
 	l.Styles.NoItems = lipgloss.NewStyle().Width(0)
 
@@ -68,6 +93,10 @@ func New(id int, debugger *debugger.Debugger) Model {
 		title:    "Call Stack",
 		list:     l,
 		debugger: debugger,
+		depth:    defaultStackDepth,
+
+		collapseLibraryFrames: true,
+		expandedGroups:        make(map[int]bool),
 	}
 }
 
@@ -116,20 +145,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		if err := m.updateContent(); err != nil {
-			return m, messages.ErrorCmd(err)
-		}
-
-		return m, nil
+		return m, m.updateContent()
 
 	case messages.RefreshContent, messages.DebuggerRestarted:
-		if err := m.updateContent(); err != nil {
-			return m, func() tea.Msg {
-				return messages.Error(err)
-			}
-		}
-
-		return m, nil
+		m.depth = defaultStackDepth
+		return m, m.updateContent()
 
 	case tea.KeyMsg:
 		var cmd tea.Cmd
@@ -137,17 +157,45 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if msg.String() == "m" {
+			return m, m.loadMoreFrames()
+		}
+
+		if msg.String() == "h" {
+			m.collapseLibraryFrames = !m.collapseLibraryFrames
+			m.expandedGroups = make(map[int]bool)
+			m.setItems()
+			return m, nil
+		}
+
 		if msg.String() == "enter" {
-			if m.list.SelectedItem() == nil {
-				return m, nil
-			}
-			item := m.list.SelectedItem().(listItem)
-			return m, func() tea.Msg {
-				return messages.FileRequested{
-					Filename: item.frame.Filename,
-					Line:     item.frame.Line,
+			switch item := m.list.SelectedItem().(type) {
+			case listItem:
+				return m, func() tea.Msg {
+					return messages.FileRequested{
+						Filename: item.frame.Filename,
+						Line:     item.frame.Line,
+					}
 				}
+			case deferItem:
+				return m, func() tea.Msg {
+					return messages.FileRequested{
+						Filename: item.deferred.Filename,
+						Line:     item.deferred.Line,
+					}
+				}
+			case moreFramesItem:
+				return m, m.loadMoreFrames()
+			case hiddenFramesItem:
+				m.expandedGroups[item.first] = true
+				m.setItems()
+				return m, nil
+			case ancestryHeaderItem:
+				m.showAncestry = !m.showAncestry
+				m.setItems()
+				return m, nil
 			}
+			return m, nil
 		}
 
 		m.list, cmd = m.list.Update(msg)
@@ -161,12 +209,40 @@ func (m Model) View() string {
 	return m.list.View()
 }
 
-func (m *Model) updateContent() error {
-	stack, err := m.debugger.CallStack()
+func (m *Model) loadMoreFrames() tea.Cmd {
+	index := m.list.Index()
+	m.depth += stackDepthStep
+
+	cmd := m.updateContent()
+	m.list.Select(index)
+	return cmd
+}
+
+// updateContent reloads the frames. The ancestors are optional, an error
+// loading them is logged to the output window and the frames are still shown.
+func (m *Model) updateContent() tea.Cmd {
+	stack, err := m.debugger.CallStack(m.depth)
 	if err != nil {
-		return fmt.Errorf("erorr updating content: %w", err)
+		return messages.ErrorCmd(fmt.Errorf("erorr updating content: %w", err))
+	}
+	if len(stack) == 0 {
+		m.list.SetItems(nil)
+		return nil
 	}
 
+	var cmd tea.Cmd
+	ancestors, err := m.debugger.Ancestors(maxAncestors, ancestorDepth)
+	switch {
+	case err == nil:
+		m.ancestorsErr = ""
+	case err.Error() != m.ancestorsErr:
+		m.ancestorsErr = err.Error()
+		cmd = messages.OutputCmd(m.debugger.Output, m.ancestorsErr)
+	}
+
+	m.stack = stack
+	m.ancestors = ancestors
+	m.expandedGroups = make(map[int]bool)
 	m.openFilename = stack[0].Filename
 	m.lineNumber = stack[0].Line
 
@@ -175,8 +251,15 @@ func (m *Model) updateContent() error {
 		openedFilename: m.openFilename,
 	})
 
-	m.list.SetItems(stackToListItems(stack))
-	return nil
+	m.setItems()
+	return cmd
+}
+
+func (m *Model) setItems() {
+	items := stackToListItems(m.stack, m.collapseLibraryFrames, m.expandedGroups)
+	items = append(items, ancestorsToListItems(m.ancestors, m.showAncestry)...)
+
+	m.list.SetItems(items)
 }
 
 type listDelegate struct {
@@ -185,11 +268,29 @@ type listDelegate struct {
 }
 
 func (d listDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
-	listItem := item.(listItem)
-
-	listItem.isFocused = m.Index() == index && d.parentFocused
-	listItem.isSelected = d.openedFilename == listItem.frame.Filename
-	fmt.Fprint(w, listItem.Render(m.Width()))
+	isFocused := m.Index() == index && d.parentFocused
+
+	switch item := item.(type) {
+	case listItem:
+		item.isFocused = isFocused
+		item.isSelected = d.openedFilename == item.frame.Filename
+		fmt.Fprint(w, item.Render(m.Width()))
+	case deferItem:
+		item.isFocused = isFocused
+		fmt.Fprint(w, item.Render(m.Width()))
+	case moreFramesItem:
+		item.isFocused = isFocused
+		fmt.Fprint(w, item.Render(m.Width()))
+	case hiddenFramesItem:
+		item.isFocused = isFocused
+		fmt.Fprint(w, item.Render(m.Width()))
+	case ancestryHeaderItem:
+		item.isFocused = isFocused
+		fmt.Fprint(w, item.Render(m.Width()))
+	case ancestorItem:
+		item.isFocused = isFocused
+		fmt.Fprint(w, item.Render(m.Width()))
+	}
 }
 
 func (d listDelegate) Height() int                               { return 2 }
@@ -214,12 +315,17 @@ func (i listItem) Render(width int) string {
 		style = frameStyleDefault
 	}
 
-	functionName := lipgloss.NewStyle().Foreground(components.ColorPurple).Render(paths.Trunc(i.frame.FunctionName, width-8))
+	functionName := paths.Trunc(i.frame.FunctionName, width-8)
+	args := truncate("("+i.frame.Arguments+")", width-8-len(functionName))
+	functionName = functionNameStyle.Render(functionName) + argumentsStyle.Render(args)
 	line := style.Render(fmt.Sprintf("%d", i.frame.Line))
 
 	if i.isFocused {
 		functionName = "▶ " + functionName
 	}
+	if i.frame.Inlined {
+		functionName += inlinedStyle.Render(" [inlined]")
+	}
 
 	displayPath := i.frame.Filename
 	if projectRoot := paths.GetProjectRoot(); projectRoot != "" {
@@ -239,14 +345,193 @@ func (i listItem) Render(width int) string {
 		Render(stackFrame)
 }
 
-func stackToListItems(stack []debugger.StackFrame) []list.Item {
-	items := make([]list.Item, len(stack))
+type deferItem struct {
+	deferred  debugger.DeferredCall
+	isFocused bool
+}
+
+func (i deferItem) FilterValue() string { return "" }
+
+func (i deferItem) Render(width int) string {
+	label := "  ↳ defer "
+	if i.isFocused {
+		label = "▶ ↳ defer "
+	}
+
+	if i.deferred.Unreadable != "" {
+		return listItemStyle.
+			Width(width).
+			Render(deferStyle.Render(label) + frameStyleDefault.Render(truncate(i.deferred.Unreadable, width-len(label))) + "\n")
+	}
+
+	functionName := functionNameStyle.Render(paths.Trunc(i.deferred.FunctionName, width-len(label)))
+	location := frameStyleDefault.Render(
+		paths.Trunc(i.deferred.Filename, width-10),
+		fmt.Sprintf(":%d", i.deferred.Line),
+	)
+
+	return listItemStyle.
+		Width(width).
+		Render(fmt.Sprintf("%s%s\n    %s", deferStyle.Render(label), functionName, location))
+}
+
+type moreFramesItem struct {
+	isFocused bool
+}
+
+func (i moreFramesItem) FilterValue() string { return "" }
+
+func (i moreFramesItem) Render(width int) string {
+	label := "… more frames"
+	if i.isFocused {
+		label = "▶ " + label
+	}
+
+	return listItemStyle.
+		Width(width).
+		Render(moreFramesStyle.Render(label + "\n  press m to load"))
+}
+
+type hiddenFramesItem struct {
+	first     int
+	count     int
+	isFocused bool
+}
 
-	for i := range stack {
-		items[i] = listItem{
-			frame: stack[i],
+func (i hiddenFramesItem) FilterValue() string { return "" }
+
+func (i hiddenFramesItem) Render(width int) string {
+	label := fmt.Sprintf("⋯ %d hidden frames", i.count)
+	if i.isFocused {
+		label = "▶ " + label
+	}
+
+	return listItemStyle.
+		Width(width).
+		Render(hiddenFramesStyle.Render(label + "\n  enter: expand"))
+}
+
+func stackToListItems(stack []debugger.StackFrame, collapse bool, expandedGroups map[int]bool) []list.Item {
+	items := make([]list.Item, 0, len(stack))
+
+	for i := 0; i < len(stack); {
+		end := i + 1
+		// the current frame is always shown, even when it is a library frame
+		if collapse && i > 0 {
+			end = i
+			for end < len(stack) && paths.Classify(stack[end].Filename) != paths.OriginUser {
+				end++
+			}
+			end = max(end, i+1)
+
+			if end-i > 1 && !expandedGroups[i] {
+				items = append(items, hiddenFramesItem{first: i, count: end - i})
+				i = end
+				continue
+			}
+		}
+
+		for ; i < end; i++ {
+			items = append(items, listItem{
+				frame: stack[i],
+			})
+
+			for _, d := range stack[i].Defers {
+				items = append(items, deferItem{deferred: d})
+			}
+		}
+	}
+
+	if len(stack) > 0 && !stack[len(stack)-1].Bottom {
+		items = append(items, moreFramesItem{})
+	}
+
+	return items
+}
+
+type ancestryHeaderItem struct {
+	creatorID int64
+	count     int
+	expanded  bool
+	isFocused bool
+}
+
+func (i ancestryHeaderItem) FilterValue() string { return "" }
+
+func (i ancestryHeaderItem) Render(width int) string {
+	label := fmt.Sprintf("⤷ created by goroutine %d", i.creatorID)
+	if i.isFocused {
+		label = "▶ " + label
+	}
+
+	action := "enter: show ancestry"
+	if i.expanded {
+		action = "enter: hide ancestry"
+	}
+
+	return listItemStyle.
+		Width(width).
+		Render(ancestryStyle.Render(label) + frameStyleDefault.Render(fmt.Sprintf("\n  %s (%d)", action, i.count)))
+}
+
+type ancestorItem struct {
+	ancestor  debugger.Ancestor
+	isFocused bool
+}
+
+func (i ancestorItem) FilterValue() string { return "" }
+
+func (i ancestorItem) Render(width int) string {
+	label := fmt.Sprintf("goroutine %d", i.ancestor.GoroutineID)
+	if i.isFocused {
+		label = "▶ " + label
+	}
+
+	details := "stack at creation:"
+	if i.ancestor.Unreadable != "" {
+		details = truncate(i.ancestor.Unreadable, width-2)
+	}
+
+	return listItemStyle.
+		Width(width).
+		Render(ancestryStyle.Render(label) + frameStyleDefault.Render("\n "+details))
+}
+
+func ancestorsToListItems(ancestors []debugger.Ancestor, expanded bool) []list.Item {
+	if len(ancestors) == 0 {
+		return nil
+	}
+
+	items := []list.Item{ancestryHeaderItem{
+		creatorID: ancestors[0].GoroutineID,
+		count:     len(ancestors),
+		expanded:  expanded,
+	}}
+	if !expanded {
+		return items
+	}
+
+	for _, a := range ancestors {
+		items = append(items, ancestorItem{ancestor: a})
+		for _, frame := range a.Frames {
+			items = append(items, listItem{frame: frame})
 		}
 	}
 
 	return items
 }
+
+func truncate(s string, maxWidth int) string {
+	runes := []rune(s)
+	if maxWidth <= 0 {
+		return ""
+	}
+	if len(runes) <= maxWidth {
+		return s
+	}
+	if maxWidth <= 3 {
+		return string(runes[:maxWidth])
+	}
+
+	return string(runes[:maxWidth-3]) + "..."
+}
@@ -0,0 +1,96 @@
+package globals
+
+import (
+	"github.com/andersonjoseph/drill/internal/components"
+	"github.com/andersonjoseph/drill/internal/messages"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var filterInputStyle lipgloss.Style = lipgloss.NewStyle().
+	Foreground(components.ColorWhite).
+	Border(lipgloss.NormalBorder()).
+	BorderForeground(components.ColorYellow)
+
+type messageNewFilter string
+
+type filterInputModel struct {
+	id        int
+	isFocused bool
+	textInput textinput.Model
+	width     int
+}
+
+func newFilterInputModel(id int) filterInputModel {
+	ti := textinput.New()
+	ti.Placeholder = "regular expression, empty for the current package"
+
+	return filterInputModel{
+		id:        id,
+		textInput: ti,
+	}
+}
+
+func (m filterInputModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m filterInputModel) Update(msg tea.Msg) (filterInputModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		var cmd tea.Cmd
+		if msg.String() == "esc" {
+			m.setFocus(false)
+			m.textInput.SetValue("")
+
+			return m, tea.Batch(
+				func() tea.Msg {
+					return messages.TextInputFocused(false)
+				},
+				func() tea.Msg {
+					return messages.WindowFocused(m.id)
+				},
+			)
+		}
+
+		if msg.String() == "enter" {
+			m.setFocus(false)
+			content := m.textInput.Value()
+			m.textInput.SetValue("")
+			return m, tea.Batch(
+				func() tea.Msg {
+					return messages.TextInputFocused(false)
+				},
+				func() tea.Msg {
+					return messages.WindowFocused(m.id)
+				},
+				func() tea.Msg {
+					return messageNewFilter(content)
+				},
+			)
+		}
+		m.textInput, cmd = m.textInput.Update(msg)
+		return m, cmd
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.textInput.Width = m.width - 3
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m filterInputModel) View() string {
+	return filterInputStyle.Render(m.textInput.View())
+}
+
+func (m *filterInputModel) setFocus(f bool) {
+	m.isFocused = f
+	m.textInput.Focus()
+}
+
+func (m *filterInputModel) setContent(c string) {
+	m.textInput.SetValue(c)
+}
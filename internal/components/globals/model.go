@@ -0,0 +1,260 @@
+package globals
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/andersonjoseph/drill/internal/components"
+	"github.com/andersonjoseph/drill/internal/debugger"
+	"github.com/andersonjoseph/drill/internal/messages"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/paginator"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const hintString = "tab: locals, /: filter, a: add to watch window, j: down, k: up"
+
+var (
+	noItemsStyle lipgloss.Style = lipgloss.NewStyle().Width(0).Foreground(components.ColorGrey)
+
+	paginatorStyleFocused lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorGreen).PaddingRight(2)
+	paginatorStyleDefault lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorWhite).PaddingRight(2)
+
+	nameStyleDefault  lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorGrey)
+	nameStyleFocused  lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorPurple).Bold(true)
+	valueStyleDefault lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorGrey)
+	valueStyleFocused lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorGreen).Bold(true)
+
+	listItemStyle = lipgloss.NewStyle()
+)
+
+type Model struct {
+	ID          int
+	title       string
+	IsFocused   bool
+	width       int
+	height      int
+	list        list.Model
+	debugger    *debugger.Debugger
+	filterInput filterInputModel
+	filter      string
+	// followPackage is set while no filter was entered, the filter then
+	// follows the package the program is stopped in
+	followPackage bool
+}
+
+func New(id int, d *debugger.Debugger) Model {
+	l := list.New([]list.Item{}, listDelegate{}, 0, 0)
+	l.SetShowHelp(false)
+	l.SetShowFilter(false)
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
+	l.Styles.PaginationStyle = paginatorStyleDefault
+	l.Styles.NoItems = lipgloss.NewStyle().Width(0)
+
+	p := paginator.New()
+	p.Type = paginator.Arabic
+	p.PerPage = 5
+	p.SetTotalPages(0)
+	p.ArabicFormat = lipgloss.NewStyle().
+		Margin(0).Padding(0).
+		Align(lipgloss.Right).
+		Render("%d of %d ")
+
+	l.Paginator = p
+
+	return Model{
+		ID:            id,
+		title:         "Globals",
+		list:          l,
+		debugger:      d,
+		filterInput:   newFilterInputModel(id),
+		followPackage: true,
+	}
+}
+
+func (m Model) Init() tea.Cmd { return nil }
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case messages.WindowFocused:
+		m.IsFocused = int(msg) == m.ID
+		m.list.SetDelegate(listDelegate{parentFocused: m.IsFocused})
+
+		if !m.IsFocused {
+			m.list.Styles.PaginationStyle = paginatorStyleDefault
+			return m, nil
+		} else {
+			m.list.Styles.PaginationStyle = paginatorStyleFocused
+		}
+
+		return m, func() tea.Msg {
+			return messages.UpdatedHint(hintString)
+		}
+
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.width = msg.Width
+		m.list.SetHeight(msg.Height)
+		m.list.SetWidth(msg.Width)
+		m.list.Styles.NoItems = noItemsStyle.Width(msg.Width)
+
+		m.filterInput, _ = m.filterInput.Update(tea.WindowSizeMsg{Width: m.width})
+		return m, nil
+
+	case messages.RefreshContent, messages.DebuggerRestarted, messages.DebuggerStepped:
+		return m, m.updateContent()
+
+	case messageNewFilter:
+		filter := strings.TrimSpace(string(msg))
+		if _, err := regexp.Compile(filter); err != nil {
+			return m, messages.ErrorCmd(fmt.Errorf("error filtering globals: %w", err))
+		}
+
+		m.filter = filter
+		m.followPackage = filter == ""
+		m.list.ResetSelected()
+		return m, m.updateContent()
+
+	case tea.KeyMsg:
+		var cmd tea.Cmd
+		if m.filterInput.isFocused {
+			m.filterInput, cmd = m.filterInput.Update(msg)
+			return m, cmd
+		}
+
+		if !m.IsFocused {
+			return m, nil
+		}
+
+		if msg.String() == "/" {
+			m.filterInput.setFocus(true)
+			if m.followPackage {
+				m.filterInput.setContent("")
+			} else {
+				m.filterInput.setContent(m.filter)
+			}
+			return m, func() tea.Msg {
+				return messages.TextInputFocused(true)
+			}
+		}
+
+		if msg.String() == "a" {
+			if m.list.SelectedItem() == nil {
+				return m, nil
+			}
+			lv := m.list.SelectedItem().(listItem)
+
+			return m, messages.WatchExpressionAddedCmd(lv.variable.Name)
+		}
+
+		m.list, cmd = m.list.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// Filtering reports whether the filter is being entered.
+func (m Model) Filtering() bool {
+	return m.filterInput.isFocused
+}
+
+func (m Model) View() string {
+	if m.filterInput.isFocused {
+		return m.filterInput.View()
+	}
+
+	return m.list.View()
+}
+
+// updateContent reloads the variables matching the filter, moving the filter
+// to the current package first when it follows it.
+func (m *Model) updateContent() tea.Cmd {
+	if m.followPackage {
+		// the last package is kept while the program is not stopped
+		if pkg, err := m.debugger.CurrentPackage(); err == nil {
+			m.filter = "^" + regexp.QuoteMeta(pkg) + `\.`
+		}
+	}
+
+	id, title := m.ID, m.windowTitle()
+	titleCmd := func() tea.Msg {
+		return messages.WindowTitleChanged{WindowID: id, Title: title}
+	}
+
+	// with no package to follow yet, listing every variable of the program
+	// would only show the runtime's
+	if m.followPackage && m.filter == "" {
+		m.list.SetItems(nil)
+		return titleCmd
+	}
+
+	vars, err := m.debugger.PackageVariables(m.filter)
+	if err != nil {
+		m.list.SetItems(nil)
+		return tea.Batch(titleCmd, messages.ErrorCmd(err))
+	}
+
+	m.list.SetItems(variablesToListItems(vars))
+	return titleCmd
+}
+
+func (m Model) windowTitle() string {
+	if m.filter == "" {
+		return m.title
+	}
+
+	return fmt.Sprintf("%s [%s]", m.title, m.filter)
+}
+
+type listDelegate struct {
+	parentFocused bool
+}
+
+func (d listDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	listItem, ok := item.(listItem)
+	if !ok {
+		return
+	}
+
+	listItem.isFocused = m.Index() == index && d.parentFocused
+	fmt.Fprint(w, listItem.Render(m.Width()))
+}
+
+func (d listDelegate) Height() int                               { return 1 }
+func (d listDelegate) Spacing() int                              { return 0 }
+func (d listDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd { return nil }
+
+type listItem struct {
+	variable  debugger.Variable
+	isFocused bool
+}
+
+func (i listItem) FilterValue() string { return "" }
+func (i listItem) Render(width int) string {
+	nameStyle, valueStyle := nameStyleDefault, valueStyleDefault
+	if i.isFocused {
+		nameStyle, valueStyle = nameStyleFocused, valueStyleFocused
+	}
+
+	name := nameStyle.Render(i.variable.Name)
+	if i.isFocused {
+		name = "▶ " + name
+	}
+
+	return listItemStyle.
+		MaxWidth(width).
+		Render(name+":", valueStyle.Render(i.variable.Value))
+}
+
+func variablesToListItems(vars []debugger.Variable) []list.Item {
+	items := make([]list.Item, len(vars))
+	for i := range vars {
+		items[i] = listItem{variable: vars[i]}
+	}
+
+	return items
+}
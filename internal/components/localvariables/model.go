@@ -3,8 +3,10 @@ package localvariables
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/andersonjoseph/drill/internal/components"
+	"github.com/andersonjoseph/drill/internal/components/globals"
 	"github.com/andersonjoseph/drill/internal/debugger"
 	"github.com/andersonjoseph/drill/internal/messages"
 	"github.com/charmbracelet/bubbles/list"
@@ -14,8 +16,8 @@ import (
 )
 
 const (
-	hintString       = "enter: inspect, j: down, k: up"
-	viewerHintString = "esc: close, j: down, k: up"
+	hintString       = "tab: globals, enter: inspect, e: edit value, f: change format, w: watch, a: add to watch window, j: down, k: up"
+	viewerHintString = "esc: close, l: expand, h: collapse, m: load more, M: load all, e: edit value, f: change format, a: add to watch window, j: down, k: up"
 )
 
 type variableStyle struct {
@@ -38,6 +40,10 @@ var (
 		value: lipgloss.NewStyle().Foreground(components.ColorGreen).Bold(true),
 	}
 
+	changedValueStyle lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorYellow).Bold(true)
+	newMarkerStyle    lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorGreen)
+	previousStyle     lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorGrey).Italic(true)
+
 	listFocusedStyle lipgloss.Style = lipgloss.NewStyle().Foreground(lipgloss.Color(components.ColorGreen))
 	listDefaultStyle lipgloss.Style = lipgloss.NewStyle()
 	listItemStyle    lipgloss.Style = lipgloss.NewStyle()
@@ -51,10 +57,21 @@ type Model struct {
 	height         int
 	list           list.Model
 	variableViewer VariableViewerModel
+	valueInput     valueInputModel
 	debugger       *debugger.Debugger
+	// values holds the locals of the last stop by variableKey, to tell what a
+	// step changed. They are only compared within function.
+	values   map[string]debugger.Variable
+	function string
+	// formats holds the display format picked for each variable by name
+	formats map[string]debugger.Format
+	// globals takes the place of the locals while showGlobals is set, it is
+	// only kept up to date while shown
+	globals     globals.Model
+	showGlobals bool
 }
 
-func New(id int, debugger *debugger.Debugger) Model {
+func New(id int, d *debugger.Debugger) Model {
 	l := list.New([]list.Item{}, listDelegate{}, 0, 0)
 	l.SetShowHelp(false)
 	l.SetShowFilter(false)
@@ -78,13 +95,54 @@ func New(id int, debugger *debugger.Debugger) Model {
 		ID:             id,
 		title:          "Local Variables",
 		list:           l,
-		debugger:       debugger,
+		debugger:       d,
 		variableViewer: newVariableViewer(id),
+		valueInput:     newValueInputModel(id),
+		formats:        make(map[string]debugger.Format),
+		globals:        globals.New(id, d),
 	}
 }
 
 func (m Model) Init() tea.Cmd { return nil }
+
+// Update passes the messages of the globals to them while they are shown.
+// The locals keep following the program meanwhile, so their changes are still
+// told from the last stop when switching back.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if !m.showGlobals {
+		return m.updateLocals(msg)
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		return m.updateLocals(msg)
+
+	case tea.KeyMsg:
+		if m.IsFocused && msg.String() == "tab" && !m.globals.Filtering() {
+			m.showGlobals = false
+
+			return m, tea.Batch(
+				func() tea.Msg {
+					return messages.UpdatedHint(hintString)
+				},
+				func() tea.Msg {
+					return messages.WindowTitleChanged{WindowID: m.ID, Title: m.title}
+				},
+			)
+		}
+
+	case messages.WindowFocused, messages.RefreshContent, messages.DebuggerRestarted, messages.DebuggerStepped:
+		// the hint and errors shown are the ones of the globals
+		l, _ := m.updateLocals(msg)
+		m = l.(Model)
+	}
+
+	g, cmd := m.globals.Update(msg)
+	m.globals = g.(globals.Model)
+	return m, cmd
+}
+
+func (m Model) updateLocals(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case messages.WindowFocused:
 		m.IsFocused = int(msg) == m.ID
@@ -110,12 +168,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.list.SetWidth(msg.Width)
 		m.list.Styles.NoItems = noItemsStyle.Width(msg.Width)
 
+		m.valueInput, _ = m.valueInput.Update(msg)
+
 		var cmd tea.Cmd
+		g, _ := m.globals.Update(msg)
+		m.globals = g.(globals.Model)
+
 		m.variableViewer, cmd = m.variableViewer.Update(msg)
 		return m, cmd
 
 	case messages.RefreshContent, messages.DebuggerRestarted, messages.DebuggerStepped:
-		if err := m.updateContent(); err != nil {
+		_, stepped := msg.(messages.DebuggerStepped)
+		if err := m.updateContent(stepped); err != nil {
 			return m, func() tea.Msg {
 				return messages.Error(err)
 			}
@@ -132,12 +196,59 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		return m, nil
 
+	case messageLoadMore:
+		v, err := m.debugger.LoadMore(msg.variable, msg.all)
+		if err != nil {
+			return m, messages.ErrorCmd(err)
+		}
+
+		m.variableViewer.replace(msg.path, v)
+		return m, nil
+
+	case messageEditValue:
+		m.valueInput.setFocus(true)
+		m.valueInput.setContent(msg.expr, msg.value)
+		return m, func() tea.Msg {
+			return messages.TextInputFocused(true)
+		}
+
+	case messageNewValue:
+		if strings.TrimSpace(msg.value) == "" {
+			return m, nil
+		}
+
+		if err := m.debugger.SetVariable(msg.expr, msg.value); err != nil {
+			return m, messages.ErrorCmd(err)
+		}
+
+		// every view showing the variable has to be reloaded
+		return m, func() tea.Msg {
+			return messages.RefreshContent{}
+		}
+
 	case tea.KeyMsg:
+		if m.valueInput.isFocused {
+			var cmd tea.Cmd
+			m.valueInput, cmd = m.valueInput.Update(msg)
+			return m, cmd
+		}
+
 		if !m.IsFocused {
 			return m, nil
 		}
 
-		if msg.String() == "enter" {
+		if msg.String() == "tab" && !m.variableViewer.isOpen {
+			m.showGlobals = true
+
+			g, hintCmd := m.globals.Update(messages.WindowFocused(m.ID))
+			m.globals = g.(globals.Model)
+			g, contentCmd := m.globals.Update(messages.RefreshContent{})
+			m.globals = g.(globals.Model)
+
+			return m, tea.Batch(hintCmd, contentCmd)
+		}
+
+		if msg.String() == "enter" && !m.variableViewer.isOpen {
 			if m.list.SelectedItem() == nil {
 				return m, nil
 			}
@@ -156,23 +267,76 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		}
 
+		if msg.String() == "a" && !m.variableViewer.isOpen {
+			if m.list.SelectedItem() == nil {
+				return m, nil
+			}
+			lv := m.list.SelectedItem().(listItem)
+
+			return m, messages.WatchExpressionAddedCmd(debugger.WithFormat(lv.format, lv.variable.Name))
+		}
+
+		if msg.String() == "f" && !m.variableViewer.isOpen {
+			if m.list.SelectedItem() == nil {
+				return m, nil
+			}
+			lv := m.list.SelectedItem().(listItem)
+
+			lv.format = debugger.NextFormat(lv.variable, lv.format)
+			m.formats[lv.variable.Name] = lv.format
+			return m, m.list.SetItem(m.list.Index(), lv)
+		}
+
+		if msg.String() == "e" && !m.variableViewer.isOpen {
+			if m.list.SelectedItem() == nil {
+				return m, nil
+			}
+			lv := m.list.SelectedItem().(listItem)
+
+			return m, func() tea.Msg {
+				return messageEditValue{expr: lv.variable.Name, value: lv.variable.Value}
+			}
+		}
+
+		if msg.String() == "w" && !m.variableViewer.isOpen {
+			if m.list.SelectedItem() == nil {
+				return m, nil
+			}
+			lv := m.list.SelectedItem().(listItem)
+
+			bp, err := m.debugger.CreateWatchpoint(lv.variable.Name, debugger.WatchWrite)
+			if err != nil {
+				return m, messages.ErrorCmd(err)
+			}
+
+			return m, messages.DebuggerBreakpointCreatedCmd(bp.ID, bp.Filename, bp.Line)
+		}
+
 		var cmd tea.Cmd
-		var cmds []tea.Cmd
+		if m.variableViewer.isOpen {
+			m.variableViewer, cmd = m.variableViewer.Update(msg)
+			return m, cmd
+		}
+
 		if msg.String() != "esc" {
 			m.list, cmd = m.list.Update(msg)
-			cmds = append(cmds, cmd)
 		}
 
-		m.variableViewer, cmd = m.variableViewer.Update(msg)
-		cmds = append(cmds, cmd)
-
-		return m, tea.Batch(cmds...)
+		return m, cmd
 	}
 
 	return m, nil
 }
 
 func (m Model) View() string {
+	if m.showGlobals {
+		return m.globals.View()
+	}
+
+	if m.valueInput.isFocused {
+		return m.valueInput.View()
+	}
+
 	if m.variableViewer.isOpen {
 		return m.variableViewer.View()
 	}
@@ -180,16 +344,48 @@ func (m Model) View() string {
 	return m.list.View()
 }
 
-func (m *Model) updateContent() error {
+// updateContent reloads the locals. After a step they are compared with the
+// ones of the previous stop.
+func (m *Model) updateContent(stepped bool) error {
 	vars, err := m.debugger.LocalVariables()
 	if err != nil {
 		return fmt.Errorf("erorr updating content: %w", err)
 	}
 
-	m.list.SetItems(variablesToListItems(vars))
+	// the locals of another function have nothing to do with these, even if
+	// they share names
+	function, _ := m.debugger.CurrentFunction()
+
+	previous := m.values
+	if !stepped || function != m.function {
+		previous = nil
+	}
+
+	m.list.SetItems(variablesToListItems(vars, previous, m.formats))
+
+	keys := variableKeys(vars)
+	m.values = make(map[string]debugger.Variable, len(vars))
+	for i, v := range vars {
+		m.values[keys[i]] = v
+	}
+	m.function = function
+
 	return nil
 }
 
+// variableKeys identifies the variables by name, numbering the ones sharing
+// a name, like shadowed locals, in the order Delve lists them.
+func variableKeys(vars []debugger.Variable) []string {
+	keys := make([]string, len(vars))
+	seen := make(map[string]int, len(vars))
+	for i, v := range vars {
+		keys[i] = fmt.Sprintf("%s#%d", v.Name, seen[v.Name])
+		seen[v.Name]++
+	}
+
+	return keys
+}
+
 type listDelegate struct {
 	parentFocused bool
 }
@@ -211,6 +407,10 @@ func (d listDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd { return nil }
 type listItem struct {
 	variable  debugger.Variable
 	isFocused bool
+	isNew     bool
+	changed   bool
+	previous  debugger.Variable
+	format    debugger.Format
 }
 
 func (i listItem) FilterValue() string { return "" }
@@ -223,24 +423,48 @@ func (i listItem) Render(width int) string {
 	}
 
 	name := style.name.Render(i.variable.Name)
+	if i.isNew {
+		name = newMarkerStyle.Render("+") + name
+	}
 	if i.isFocused {
 		name = "▶ " + name
 	}
-	value := style.value.
-		Render(i.variable.Value)
+
+	valueStyle := style.value
+	if i.changed {
+		valueStyle = changedValueStyle
+	}
+	value := valueStyle.Render(i.variable.Format(i.format).Value)
+
+	if i.changed && i.isFocused {
+		value += previousStyle.Render(" (was " + i.previous.Format(i.format).Value + ")")
+	}
 
 	return listItemStyle.
 		MaxWidth(width).
 		Render(name+":", value)
 }
 
-func variablesToListItems(vars []debugger.Variable) []list.Item {
+// variablesToListItems flags the variables that are new or changed compared
+// to previous, a nil previous flags nothing.
+func variablesToListItems(vars []debugger.Variable, previous map[string]debugger.Variable, formats map[string]debugger.Format) []list.Item {
 	items := make([]list.Item, len(vars))
+	keys := variableKeys(vars)
 
 	for i := range vars {
-		items[i] = listItem{
+		item := listItem{
 			variable: vars[i],
+			format:   formats[vars[i].Name],
 		}
+
+		if previous != nil {
+			v, ok := previous[keys[i]]
+			item.isNew = !ok
+			item.changed = ok && v.Value != vars[i].Value
+			item.previous = v
+		}
+
+		items[i] = item
 	}
 
 	return items
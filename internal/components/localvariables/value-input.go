@@ -0,0 +1,114 @@
+package localvariables
+
+import (
+	"github.com/andersonjoseph/drill/internal/components"
+	"github.com/andersonjoseph/drill/internal/messages"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var valueInputStyle lipgloss.Style = lipgloss.NewStyle().
+	Foreground(components.ColorWhite).
+	Border(lipgloss.NormalBorder()).
+	BorderForeground(components.ColorYellow)
+
+// messageNewValue asks to assign value to the variable expr refers to.
+type messageNewValue struct {
+	expr  string
+	value string
+}
+
+// messageEditValue opens the value input for the variable expr refers to.
+type messageEditValue struct {
+	expr  string
+	value string
+}
+
+type valueInputModel struct {
+	id        int
+	isFocused bool
+	textInput textinput.Model
+	width     int
+	expr      string
+}
+
+func newValueInputModel(id int) valueInputModel {
+	ti := textinput.New()
+	ti.Placeholder = "value"
+
+	return valueInputModel{
+		id:        id,
+		textInput: ti,
+	}
+}
+
+func (m valueInputModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m valueInputModel) Update(msg tea.Msg) (valueInputModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		var cmd tea.Cmd
+		if msg.String() == "esc" {
+			m.setFocus(false)
+			m.textInput.SetValue("")
+
+			return m, tea.Batch(
+				func() tea.Msg {
+					return messages.TextInputFocused(false)
+				},
+				func() tea.Msg {
+					return messages.WindowFocused(m.id)
+				},
+			)
+		}
+
+		if msg.String() == "enter" {
+			m.setFocus(false)
+			content := m.textInput.Value()
+			m.textInput.SetValue("")
+			expr := m.expr
+			return m, tea.Batch(
+				func() tea.Msg {
+					return messages.TextInputFocused(false)
+				},
+				func() tea.Msg {
+					return messages.WindowFocused(m.id)
+				},
+				func() tea.Msg {
+					return messageNewValue{expr: expr, value: content}
+				},
+			)
+		}
+		m.textInput, cmd = m.textInput.Update(msg)
+		return m, cmd
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.textInput.Width = max(0, m.width-3-len(m.textInput.Prompt))
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m valueInputModel) View() string {
+	return valueInputStyle.Render(m.textInput.View())
+}
+
+func (m *valueInputModel) setFocus(f bool) {
+	m.isFocused = f
+	m.textInput.Focus()
+}
+
+// setContent prepares the input to edit expr, starting from its current
+// value.
+func (m *valueInputModel) setContent(expr string, value string) {
+	m.expr = expr
+	m.textInput.Prompt = expr + " = "
+	m.textInput.Width = max(0, m.width-3-len(m.textInput.Prompt))
+	m.textInput.SetValue(value)
+	m.textInput.CursorEnd()
+}
@@ -2,32 +2,66 @@ package localvariables
 
 import (
 	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 
-	"github.com/alecthomas/chroma/v2/quick"
+	"github.com/andersonjoseph/drill/internal/components"
 	"github.com/andersonjoseph/drill/internal/debugger"
 	"github.com/andersonjoseph/drill/internal/messages"
-	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/muesli/reflow/wordwrap"
+	"github.com/charmbracelet/lipgloss"
 )
 
-type messageNewContent debugger.Variable
+var (
+	treeNameStyle        = lipgloss.NewStyle().Foreground(components.ColorWhite)
+	treeNameFocusedStyle = lipgloss.NewStyle().Foreground(components.ColorPurple).Bold(true)
+	treeTypeStyle        = lipgloss.NewStyle().Foreground(components.ColorGrey)
+	treeValueStyle       = lipgloss.NewStyle().Foreground(components.ColorGreen)
+	treeMarkerStyle      = lipgloss.NewStyle().Foreground(components.ColorYellow)
+	treeLineStyle        = lipgloss.NewStyle()
+	treeMoreStyle        = lipgloss.NewStyle().Foreground(components.ColorGrey).Italic(true)
+)
+
+// messageLoadMore asks for the rest of the variable at path, see
+// debugger.LoadMore.
+type messageLoadMore struct {
+	path     string
+	variable debugger.Variable
+	all      bool
+}
+
+// treeNode is a variable as it is shown in the tree, path identifies it
+// across refreshes so expanded nodes stay expanded while stepping. more nodes
+// stand for the elements of their parent that are not loaded yet.
+type treeNode struct {
+	variable debugger.Variable
+	depth    int
+	path     string
+	more     bool
+}
 
 type VariableViewerModel struct {
 	id        int
 	isOpen    bool
 	isFocused bool
-	viewport  viewport.Model
+	width     int
+	height    int
 	variable  debugger.Variable
-	content   string
+	expanded  map[string]bool
+	formats   map[string]debugger.Format
+	nodes     []treeNode
+	cursor    int
+	offset    int
 }
 
 func newVariableViewer(id int) VariableViewerModel {
 	return VariableViewerModel{
 		id:       id,
 		isOpen:   false,
-		viewport: viewport.New(0, 0),
+		expanded: make(map[string]bool),
+		formats:  make(map[string]debugger.Format),
 	}
 }
 
@@ -36,13 +70,11 @@ func (m VariableViewerModel) Init() tea.Cmd {
 }
 
 func (m VariableViewerModel) Update(msg tea.Msg) (VariableViewerModel, tea.Cmd) {
-	var cmd tea.Cmd
-
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		m.viewport.Height = msg.Height
-		m.viewport.Width = msg.Width
-		m.viewport.SetContent(wordwrap.String(m.content, m.viewport.Width))
+		m.width = msg.Width
+		m.height = msg.Height
+		m.scrollToCursor()
 		return m, nil
 
 	case tea.KeyMsg:
@@ -50,7 +82,8 @@ func (m VariableViewerModel) Update(msg tea.Msg) (VariableViewerModel, tea.Cmd)
 			return m, nil
 		}
 
-		if msg.String() == "esc" {
+		switch msg.String() {
+		case "esc":
 			m.isOpen = false
 			return m, tea.Batch(
 				func() tea.Msg {
@@ -59,31 +92,123 @@ func (m VariableViewerModel) Update(msg tea.Msg) (VariableViewerModel, tea.Cmd)
 					return messages.WindowTitleChanged{WindowID: m.id, Title: "Local Variables"}
 				},
 			)
-		}
 
-		m.viewport, cmd = m.viewport.Update(msg)
-		return m, cmd
+		case "j", "down":
+			m.moveCursor(1)
+
+		case "k", "up":
+			m.moveCursor(-1)
+
+		case "g", "home":
+			m.moveCursor(-len(m.nodes))
+
+		case "G", "end":
+			m.moveCursor(len(m.nodes))
+
+		case "l", "right", "enter":
+			return m, m.expand()
+
+		case "h", "left":
+			m.collapse()
+
+		case "m", "M":
+			return m, m.loadMore(msg.String() == "M")
+
+		case "e":
+			if len(m.nodes) == 0 || m.nodes[m.cursor].more || m.nodes[m.cursor].variable.Expr == "" {
+				return m, nil
+			}
+			node := m.nodes[m.cursor]
+			return m, func() tea.Msg {
+				return messageEditValue{expr: node.variable.Expr, value: node.variable.Value}
+			}
+
+		case "f":
+			if len(m.nodes) == 0 || m.nodes[m.cursor].more {
+				return m, nil
+			}
+			node := m.nodes[m.cursor]
+			m.formats[node.path] = debugger.NextFormat(node.variable, m.formats[node.path])
+
+		case "a":
+			if len(m.nodes) == 0 || m.nodes[m.cursor].more || m.nodes[m.cursor].variable.Expr == "" {
+				return m, nil
+			}
+			node := m.nodes[m.cursor]
+			return m, messages.WatchExpressionAddedCmd(debugger.WithFormat(m.formats[node.path], node.variable.Expr))
+		}
 	}
 
-	m.viewport, cmd = m.viewport.Update(msg)
-	return m, cmd
+	return m, nil
 }
 
 func (m VariableViewerModel) View() string {
-	return m.viewport.View()
+	if m.height <= 0 {
+		return ""
+	}
+
+	end := min(len(m.nodes), m.offset+m.height)
+
+	lines := make([]string, 0, m.height)
+	for i := m.offset; i < end; i++ {
+		lines = append(lines, m.renderNode(m.nodes[i], i == m.cursor))
+	}
+
+	return lipgloss.NewStyle().Height(m.height).Render(strings.Join(lines, "\n"))
 }
 
-func (m *VariableViewerModel) setContent(v debugger.Variable) error {
-	m.variable = v
+func (m VariableViewerModel) renderNode(node treeNode, focused bool) string {
+	if node.more {
+		style := treeMoreStyle
+		if focused && m.isFocused {
+			style = treeNameFocusedStyle
+		}
 
-	colorizedContent, err := colorize(v.MultilineValue)
-	if err != nil {
-		return fmt.Errorf("error setting content: %w", err)
+		more := fmt.Sprintf("… %d more, m: load more, M: load all", node.variable.Missing)
+		return treeLineStyle.MaxWidth(m.width).Render(strings.Repeat("  ", node.depth) + style.Render(more))
 	}
-	m.content = colorizedContent
 
-	m.viewport.SetContent(wordwrap.String(m.content, m.viewport.Width))
-	return nil
+	marker := "  "
+	if hasChildren(node.variable) {
+		if m.expanded[node.path] {
+			marker = "▾ "
+		} else {
+			marker = "▸ "
+		}
+	}
+
+	nameStyle := treeNameStyle
+	if focused && m.isFocused {
+		nameStyle = treeNameFocusedStyle
+	}
+
+	line := strings.Repeat("  ", node.depth) +
+		treeMarkerStyle.Render(marker) +
+		nameStyle.Render(node.variable.Name)
+
+	if node.variable.Type != "" {
+		line += " " + treeTypeStyle.Render(node.variable.Type)
+	}
+	line += " = " + treeValueStyle.Render(node.variable.Format(m.formats[node.path]).Value)
+
+	if node.variable.Kind == reflect.String && node.variable.Missing > 0 {
+		line += treeMoreStyle.Render(" m: load full value")
+	}
+
+	return treeLineStyle.MaxWidth(m.width).Render(line)
+}
+
+func (m *VariableViewerModel) setContent(v debugger.Variable) {
+	// forget what was expanded when a different variable is inspected
+	if v.Name != m.variable.Name {
+		m.expanded = map[string]bool{"": true}
+		m.formats = make(map[string]debugger.Format)
+		m.cursor = 0
+		m.offset = 0
+	}
+
+	m.variable = v
+	m.flatten()
 }
 
 func (m *VariableViewerModel) setIsOpen(v bool) {
@@ -94,13 +219,145 @@ func (m *VariableViewerModel) setFocus(v bool) {
 	m.isFocused = v
 }
 
-func colorize(content string) (string, error) {
-	sb := strings.Builder{}
+func (m *VariableViewerModel) flatten() {
+	m.nodes = nil
+
+	var walk func(v debugger.Variable, depth int, path string)
+	walk = func(v debugger.Variable, depth int, path string) {
+		m.nodes = append(m.nodes, treeNode{variable: v, depth: depth, path: path})
+		if !m.expanded[path] {
+			return
+		}
 
-	err := quick.Highlight(&sb, content, "go", "terminal8", "native")
-	if err != nil {
-		return "", fmt.Errorf("error highlighting the source code: %w", err)
+		for i, child := range v.Children {
+			walk(child, depth+1, path+"/"+strconv.Itoa(i))
+		}
+
+		if v.Kind != reflect.String && v.Missing > 0 {
+			m.nodes = append(m.nodes, treeNode{variable: v, depth: depth + 1, path: path, more: true})
+		}
 	}
+	walk(m.variable, 0, "")
+
+	m.cursor = min(m.cursor, len(m.nodes)-1)
+	m.scrollToCursor()
+}
 
-	return sb.String(), nil
+func (m *VariableViewerModel) moveCursor(delta int) {
+	m.cursor = max(0, min(len(m.nodes)-1, m.cursor+delta))
+	m.scrollToCursor()
+}
+
+// expand opens the node under the cursor or, when it is already open, moves
+// to its first child. Nodes nested too deep to be loaded are loaded first.
+func (m *VariableViewerModel) expand() tea.Cmd {
+	if len(m.nodes) == 0 {
+		return nil
+	}
+
+	node := m.nodes[m.cursor]
+	if node.more {
+		return m.loadMore(false)
+	}
+
+	if !hasChildren(node.variable) {
+		return nil
+	}
+
+	if m.expanded[node.path] {
+		m.moveCursor(1)
+		return nil
+	}
+
+	m.expanded[node.path] = true
+	if node.variable.Unloaded {
+		return m.loadMore(false)
+	}
+
+	m.flatten()
+	return nil
+}
+
+func (m *VariableViewerModel) loadMore(all bool) tea.Cmd {
+	if len(m.nodes) == 0 {
+		return nil
+	}
+
+	node := m.nodes[m.cursor]
+	if node.variable.Missing == 0 && !node.variable.Unloaded {
+		return nil
+	}
+
+	return func() tea.Msg {
+		return messageLoadMore{path: node.path, variable: node.variable, all: all}
+	}
+}
+
+// replace swaps the variable at path for v, once more of it was loaded.
+func (m *VariableViewerModel) replace(path string, v debugger.Variable) {
+	if path == "" {
+		m.variable = v
+		m.flatten()
+		return
+	}
+
+	m.variable = replaceAt(m.variable, strings.Split(strings.TrimPrefix(path, "/"), "/"), v)
+	m.flatten()
+}
+
+func replaceAt(parent debugger.Variable, path []string, v debugger.Variable) debugger.Variable {
+	i, err := strconv.Atoi(path[0])
+	if err != nil || i < 0 || i >= len(parent.Children) {
+		return parent
+	}
+
+	children := slices.Clone(parent.Children)
+	if len(path) == 1 {
+		children[i] = v
+	} else {
+		children[i] = replaceAt(children[i], path[1:], v)
+	}
+	parent.Children = children
+
+	return parent
+}
+
+func hasChildren(v debugger.Variable) bool {
+	return len(v.Children) > 0 || v.Unloaded || (v.Kind != reflect.String && v.Missing > 0)
+}
+
+// collapse closes the node under the cursor or, when it is already closed,
+// moves to its parent.
+func (m *VariableViewerModel) collapse() {
+	if len(m.nodes) == 0 {
+		return
+	}
+
+	node := m.nodes[m.cursor]
+	if m.expanded[node.path] && hasChildren(node.variable) && !node.more {
+		delete(m.expanded, node.path)
+		m.flatten()
+		return
+	}
+
+	for i := m.cursor - 1; i >= 0; i-- {
+		if m.nodes[i].depth < node.depth {
+			m.cursor = i
+			m.scrollToCursor()
+			return
+		}
+	}
+}
+
+func (m *VariableViewerModel) scrollToCursor() {
+	if m.height <= 0 {
+		return
+	}
+
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
 }
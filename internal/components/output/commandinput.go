@@ -3,10 +3,12 @@ package output
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/alecthomas/chroma/v2/quick"
 	"github.com/andersonjoseph/drill/internal/components"
+	"github.com/andersonjoseph/drill/internal/config"
 	"github.com/andersonjoseph/drill/internal/debugger"
 	"github.com/andersonjoseph/drill/internal/messages"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -23,6 +25,7 @@ var (
 			Foreground(components.ColorWhite)
 	commandStyle lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorGrey)
 	errorStyle   lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorRed)
+	warningStyle lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorOrange)
 )
 
 type CommandInputModel struct {
@@ -33,6 +36,9 @@ type CommandInputModel struct {
 
 	history    []string
 	historyPos int
+
+	// callWarned is set once the side effects of call were warned about
+	callWarned bool
 }
 
 func newCommandInputModel(id int, d *debugger.Debugger) CommandInputModel {
@@ -108,6 +114,48 @@ func (m CommandInputModel) Update(msg tea.Msg) (CommandInputModel, tea.Cmd) {
 				if err := m.commandPrint(input, args); err != nil {
 					m.sendOutput(errorStyle.Render(err.Error()))
 				}
+			case "break", "b":
+				cmd, err := m.commandBreak(input, args)
+				if err != nil {
+					m.sendOutput(errorStyle.Render(err.Error()))
+				}
+				return m, cmd
+			case "watch":
+				cmd, err := m.commandWatch(input, args)
+				if err != nil {
+					m.sendOutput(errorStyle.Render(err.Error()))
+				}
+				return m, cmd
+			case "display":
+				if len(args) == 0 {
+					m.sendOutput(errorStyle.Render("error: 'display' command requires an expression"))
+					return m, nil
+				}
+				expr := strings.Join(args, " ")
+				m.sendOutput(fmt.Sprintf("%s\nwatching %s", commandStyle.Render(input), expr))
+				return m, messages.WatchExpressionAddedCmd(expr)
+			case "call":
+				cmd, ran, err := m.commandCall(input, args)
+				if err != nil {
+					m.sendOutput(errorStyle.Render(err.Error()))
+				}
+				// the warning goes along until a call runs
+				if ran {
+					m.callWarned = true
+				}
+				return m, cmd
+			case "set":
+				cmd, err := m.commandSet(input, args)
+				if err != nil {
+					m.sendOutput(errorStyle.Render(err.Error()))
+				}
+				return m, cmd
+			case "config":
+				cmd, err := m.commandConfig(input, args)
+				if err != nil {
+					m.sendOutput(errorStyle.Render(err.Error()))
+				}
+				return m, cmd
 			default:
 				m.sendOutput(
 					errorStyle.Render(
@@ -167,11 +215,16 @@ func (m CommandInputModel) commandPrint(input string, args []string) error {
 		return errors.New("error: 'print' command requires an argument")
 	}
 
-	expr := strings.Join(args, " ")
+	format, expr := debugger.SplitFormat(strings.Join(args, " "))
+	if expr == "" {
+		return errors.New("error: 'print' command requires an expression")
+	}
+
 	v, err := m.debugger.EvalVariable(expr)
 	if err != nil {
 		return err
 	}
+	v = v.Format(format)
 
 	colorizedValue, err := Colorize(v.MultilineValue)
 	if err != nil {
@@ -187,6 +240,264 @@ func (m CommandInputModel) commandPrint(input string, args []string) error {
 	return nil
 }
 
+func (m CommandInputModel) commandBreak(input string, args []string) (tea.Cmd, error) {
+	if len(args) == 0 {
+		return nil, errors.New("error: 'break' command requires a location")
+	}
+
+	bps, err := m.debugger.CreateBreakpointsAt(strings.Join(args, " "))
+	if err != nil && len(bps) == 0 {
+		return nil, err
+	}
+
+	cmds := make([]tea.Cmd, 0, len(bps))
+	lines := make([]string, 0, len(bps)+1)
+	for _, bp := range bps {
+		cmds = append(cmds, messages.DebuggerBreakpointCreatedCmd(bp.ID, bp.Filename, bp.Line))
+		lines = append(lines, fmt.Sprintf("breakpoint %d set at %s:%d", bp.ID, bp.Filename, bp.Line))
+	}
+	if err != nil {
+		lines = append(lines, errorStyle.Render(err.Error()))
+	}
+
+	m.sendOutput(fmt.Sprintf(
+		"%s\n%s",
+		commandStyle.Render(input),
+		strings.Join(lines, "\n"),
+	))
+
+	return tea.Batch(cmds...), nil
+}
+
+func (m CommandInputModel) commandWatch(input string, args []string) (tea.Cmd, error) {
+	wtype := debugger.WatchWrite
+	if len(args) > 0 {
+		switch args[0] {
+		case "-r":
+			wtype, args = debugger.WatchRead, args[1:]
+		case "-w":
+			wtype, args = debugger.WatchWrite, args[1:]
+		case "-rw":
+			wtype, args = debugger.WatchReadWrite, args[1:]
+		}
+	}
+
+	if len(args) == 0 {
+		return nil, errors.New("error: 'watch' command requires an expression")
+	}
+
+	bp, err := m.debugger.CreateWatchpoint(strings.Join(args, " "), wtype)
+	if err != nil {
+		return nil, err
+	}
+
+	m.sendOutput(fmt.Sprintf(
+		"%s\nwatchpoint %d set on %s [%s]",
+		commandStyle.Render(input),
+		bp.ID,
+		bp.WatchExpr,
+		bp.WatchType,
+	))
+
+	return messages.DebuggerBreakpointCreatedCmd(bp.ID, bp.Filename, bp.Line), nil
+}
+
+// commandCall reports whether the function ran in the program, it doesn't
+// when Delve fails to inject the call.
+func (m CommandInputModel) commandCall(input string, args []string) (tea.Cmd, bool, error) {
+	if len(args) == 0 {
+		return nil, false, errors.New("error: 'call' command requires a function call")
+	}
+
+	lines := []string{commandStyle.Render(input)}
+	if !m.callWarned {
+		lines = append(lines, warningStyle.Render("warning: the function runs in the program, any side effects it has are kept"))
+	}
+
+	values, hits, err := m.debugger.Call(strings.Join(args, " "))
+	if errors.Is(err, debugger.ErrCallInterrupted) {
+		lines = append(lines, errorStyle.Render(err.Error()))
+		m.sendOutput(strings.Join(lines, "\n"))
+
+		// the program is stopped somewhere else now, at breakpoints to be
+		// handled like on any other stop
+		return messages.DebuggerSteppedCmd(hits), true, nil
+	}
+	if err != nil {
+		// a single output, the warning goes along with the error
+		lines = append(lines, errorStyle.Render(err.Error()))
+		m.sendOutput(strings.Join(lines, "\n"))
+		return nil, false, nil
+	}
+
+	if len(values) == 0 {
+		lines = append(lines, "(no return values)")
+	}
+	for _, v := range values {
+		value, err := Colorize(v.MultilineValue)
+		if err != nil {
+			value = v.MultilineValue
+		}
+		lines = append(lines, fmt.Sprintf("%s = %s", v.Name, value))
+	}
+
+	m.sendOutput(strings.Join(lines, "\n"))
+
+	// the call may have changed what the other windows show
+	return func() tea.Msg {
+		return messages.RefreshContent{}
+	}, true, nil
+}
+
+func (m CommandInputModel) commandSet(input string, args []string) (tea.Cmd, error) {
+	expr, value, ok := splitAssignment(strings.Join(args, " "))
+	if !ok {
+		return nil, errors.New("error: usage: set <expr> = <value>")
+	}
+
+	if err := m.debugger.SetVariable(expr, value); err != nil {
+		return nil, err
+	}
+
+	v, err := m.debugger.EvalVariable(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	m.sendOutput(fmt.Sprintf("%s\n%s = %s", commandStyle.Render(input), expr, v.Value))
+
+	// the locals, watches and anything else showing the variable are stale
+	return func() tea.Msg {
+		return messages.RefreshContent{}
+	}, nil
+}
+
+// splitAssignment splits "expr = value" at its assignment, skipping the "="
+// of comparison operators the expressions may contain and the ones inside
+// string and character literals or brackets, like in m["a=b"].
+func splitAssignment(s string) (string, string, bool) {
+	var quote byte
+	depth := 0
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+			continue
+
+		case c == '(' || c == '[' || c == '{':
+			depth++
+			continue
+
+		case c == ')' || c == ']' || c == '}':
+			depth--
+			continue
+
+		case c != '=' || depth > 0:
+			continue
+		}
+
+		if i+1 < len(s) && s[i+1] == '=' {
+			i++
+			continue
+		}
+		if i > 0 && strings.ContainsRune("!<>:", rune(s[i-1])) {
+			continue
+		}
+
+		expr, value := strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:])
+		if expr == "" || value == "" {
+			return "", "", false
+		}
+		return expr, value, true
+	}
+
+	return "", "", false
+}
+
+func (m CommandInputModel) commandConfig(input string, args []string) (tea.Cmd, error) {
+	cfg := m.debugger.LoadConfig()
+
+	if len(args) == 0 {
+		m.sendOutput(fmt.Sprintf(
+			"%s\nfollowPointers = %t\nmaxVariableRecurse = %d\nmaxStringLen = %d\nmaxArrayValues = %d\nmaxStructFields = %d",
+			commandStyle.Render(input),
+			cfg.FollowPointers,
+			cfg.MaxVariableRecurse,
+			cfg.MaxStringLen,
+			cfg.MaxArrayValues,
+			cfg.MaxStructFields,
+		))
+		return nil, nil
+	}
+
+	if len(args) != 2 {
+		return nil, errors.New("error: usage: config [<setting> <value>]")
+	}
+
+	if err := setLoadConfig(&cfg, args[0], args[1]); err != nil {
+		return nil, err
+	}
+	m.debugger.SetLoadConfig(cfg)
+
+	m.sendOutput(fmt.Sprintf("%s\n%s = %s", commandStyle.Render(input), args[0], args[1]))
+
+	// reload what is on screen with the new limits
+	return func() tea.Msg {
+		return messages.RefreshContent{}
+	}, nil
+}
+
+func setLoadConfig(cfg *config.LoadConfig, setting string, value string) error {
+	if strings.EqualFold(setting, "followPointers") {
+		follow, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("error: followPointers must be true or false, got %q", value)
+		}
+		cfg.FollowPointers = follow
+		return nil
+	}
+
+	// Delve loads every field of a struct when maxStructFields is -1
+	minimum := 0
+	if strings.EqualFold(setting, "maxStructFields") {
+		minimum = -1
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < minimum {
+		return fmt.Errorf("error: %s must be a number >= %d, got %q", setting, minimum, value)
+	}
+
+	switch strings.ToLower(setting) {
+	case "maxvariablerecurse":
+		cfg.MaxVariableRecurse = n
+	case "maxstringlen":
+		cfg.MaxStringLen = n
+	case "maxarrayvalues":
+		cfg.MaxArrayValues = n
+	case "maxstructfields":
+		cfg.MaxStructFields = n
+	default:
+		return fmt.Errorf("error: unknown setting '%s'", setting)
+	}
+
+	return nil
+}
+
+func (m *CommandInputModel) setValue(value string) {
+	m.textInput.SetValue(value)
+	m.textInput.CursorEnd()
+}
+
 func (m CommandInputModel) View() string {
 	return m.textInput.View()
 }
@@ -0,0 +1,41 @@
+package output
+
+import "testing"
+
+func TestSplitAssignment(t *testing.T) {
+	tests := []struct {
+		input string
+		expr  string
+		value string
+		ok    bool
+	}{
+		{input: "n = 1", expr: "n", value: "1", ok: true},
+		{input: "n=1", expr: "n", value: "1", ok: true},
+		{input: "  s.field =  \"x\"  ", expr: "s.field", value: "\"x\"", ok: true},
+		{input: "ok = a == b", expr: "ok", value: "a == b", ok: true},
+		{input: "ok = a != b", expr: "ok", value: "a != b", ok: true},
+		{input: "ok = a <= b", expr: "ok", value: "a <= b", ok: true},
+		{input: "ok = a >= b", expr: "ok", value: "a >= b", ok: true},
+		{input: `m["a=b"] = 1`, expr: `m["a=b"]`, value: "1", ok: true},
+		{input: `m["a\"=b"] = 1`, expr: `m["a\"=b"]`, value: "1", ok: true},
+		{input: "m[`a=b`] = 1", expr: "m[`a=b`]", value: "1", ok: true},
+		{input: "m['='] = 1", expr: "m['=']", value: "1", ok: true},
+		{input: "m[k==1] = 2", expr: "m[k==1]", value: "2", ok: true},
+		{input: `s = "a=b"`, expr: "s", value: `"a=b"`, ok: true},
+		{input: "n == 1", ok: false},
+		{input: "n := 1", ok: false},
+		{input: `m["a=b"]`, ok: false},
+		{input: "= 1", ok: false},
+		{input: "n =", ok: false},
+		{input: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			expr, value, ok := splitAssignment(tt.input)
+			if ok != tt.ok || expr != tt.expr || value != tt.value {
+				t.Errorf("splitAssignment(%q) = %q, %q, %t, want %q, %q, %t", tt.input, expr, value, ok, tt.expr, tt.value, tt.ok)
+			}
+		})
+	}
+}
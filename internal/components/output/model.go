@@ -19,6 +19,7 @@ var (
 	stdoutLabelStyle  lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorGrey)
 	stderrLabelStyle  lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorOrange)
 	commandLabelStyle lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorYellow)
+	logLabelStyle     lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorPurple)
 )
 
 type Model struct {
@@ -105,6 +106,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		return m, waitForDebuggerOutput(m.debugger.Output)
 
+	case messages.DebuggerBreakpointHit:
+		if msg.Breakpoint.LogMessage == "" {
+			return m, nil
+		}
+
+		label := logLabelStyle.Render("[log] ")
+		m.content += "\n" + label + debugger.FormatLogMessage(msg.Breakpoint.LogMessage, msg.Variables)
+		m.viewport.SetContent(m.content)
+		m.viewport.GotoBottom()
+
+		return m, nil
+
+	case messages.CommandRequested:
+		var cmd tea.Cmd
+		m.commandInput, cmd = m.commandInput.Update(messages.WindowFocused(m.ID))
+		m.commandInput.setValue(string(msg))
+
+		return m, tea.Batch(cmd, func() tea.Msg {
+			return messages.WindowFocused(m.ID)
+		})
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -5,11 +5,12 @@ import (
 
 	"github.com/andersonjoseph/drill/internal/debugger"
 	"github.com/andersonjoseph/drill/internal/messages"
+	"github.com/andersonjoseph/drill/internal/scope"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 const (
-	hintString = "c: continue, n: next, r: restart, b: create/toggle breakpoint, d: delete breakpoint, s: step in, S: step out, enter: select breakpoint, j: down, k: up"
+	hintString = "c: continue, n: next, r: restart, b: create/toggle breakpoint, d: delete breakpoint, a: add to watch window, s: step in, S: step out, enter: select breakpoint, j: down, k: up"
 )
 
 type Model struct {
@@ -86,15 +87,15 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 	if msg.String() == "n" {
-		if err := m.next(); err != nil {
+		hits, err := m.next()
+		if err != nil {
 			return m, messages.ErrorCmd(err)
 		}
-		return m, func() tea.Msg { return messages.DebuggerStepped{} }
+		return m, messages.DebuggerSteppedCmd(hits)
 	}
 
 	if msg.String() == "c" {
-		m.debugger.Continue()
-		return m, func() tea.Msg { return messages.DebuggerStepped{} }
+		return m, messages.DebuggerSteppedCmd(m.debugger.Continue())
 	}
 
 	if msg.String() == "r" {
@@ -104,6 +105,19 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, func() tea.Msg { return messages.DebuggerRestarted{} }
 	}
 
+	if msg.String() == "a" {
+		// suggest the first variable on the line, the user can edit it before
+		// running the command
+		var expr string
+		if names, err := scope.LineIdentifiers(m.viewport.filename, m.viewport.CurrentLineNumber()); err == nil && len(names) > 0 {
+			expr = names[0]
+		}
+
+		return m, func() tea.Msg {
+			return messages.CommandRequested("display " + expr)
+		}
+	}
+
 	if msg.String() == "b" {
 		return m, m.createOrToggleBreakpoint()
 	}
@@ -113,23 +127,21 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 
 	if msg.String() == "s" {
-		if err := m.debugger.StepIn(); err != nil {
+		hits, err := m.debugger.StepIn()
+		if err != nil {
 			return m, messages.ErrorCmd(err)
 		}
 
-		return m, func() tea.Msg {
-			return messages.DebuggerStepped{}
-		}
+		return m, messages.DebuggerSteppedCmd(hits)
 	}
 
 	if msg.String() == "S" {
-		if err := m.debugger.StepOut(); err != nil {
+		hits, err := m.debugger.StepOut()
+		if err != nil {
 			return m, messages.ErrorCmd(err)
 		}
 
-		return m, func() tea.Msg {
-			return messages.DebuggerStepped{}
-		}
+		return m, messages.DebuggerSteppedCmd(hits)
 	}
 
 	if msg.String() == "enter" {
@@ -151,13 +163,13 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 func (m Model) View() string { return m.viewport.View() }
 
-func (m *Model) next() error {
-	err := m.debugger.Next()
+func (m *Model) next() ([]debugger.BreakpointHit, error) {
+	hits, err := m.debugger.Next()
 	if err != nil {
-		return fmt.Errorf("error stepping over: %w", err)
+		return nil, fmt.Errorf("error stepping over: %w", err)
 	}
 
-	return nil
+	return hits, nil
 }
 
 func (m Model) createOrToggleBreakpoint() tea.Cmd {
@@ -168,14 +180,21 @@ func (m Model) createOrToggleBreakpoint() tea.Cmd {
 
 	if !ok {
 		currentLine := m.viewport.CurrentLineNumber()
-		bp, err := m.debugger.CreateBreakpoint(m.viewport.filename, currentLine)
+		bp, err := m.debugger.CreateBreakpointNear(m.viewport.filename, currentLine)
 		if err != nil {
 			return func() tea.Msg {
 				return messages.Error(fmt.Errorf("error creating breakpoint: %w", err))
 			}
 		}
 
-		return messages.DebuggerBreakpointCreatedCmd(bp.ID, bp.Filename, currentLine)
+		if bp.Line == currentLine {
+			return messages.DebuggerBreakpointCreatedCmd(bp.ID, bp.Filename, bp.Line)
+		}
+
+		return tea.Batch(
+			messages.DebuggerBreakpointCreatedCmd(bp.ID, bp.Filename, bp.Line),
+			messages.OutputCmd(m.debugger.Output, fmt.Sprintf("no code at line %d, breakpoint moved to line %d", currentLine, bp.Line)),
+		)
 	}
 
 	m.debugger.ToggleBreakpoint(bp.ID)
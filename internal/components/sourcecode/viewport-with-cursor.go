@@ -15,6 +15,9 @@ import (
 const (
 	arrowSymbol         = " 🢂 "
 	breakpointDotSymbol = " ⏺ "
+	logpointDotSymbol   = " ◆ "
+	temporaryDotSymbol  = " ○ "
+	dependentDotSymbol  = " ◐ "
 )
 
 var (
@@ -25,6 +28,14 @@ var (
 	enabledBreakpointDot  = lipgloss.NewStyle().Foreground(components.ColorRed).Render(breakpointDotSymbol)
 	disabledBreakpointDot = lipgloss.NewStyle().Foreground(components.ColorGrey).Render(breakpointDotSymbol)
 
+	enabledLogpointDot  = lipgloss.NewStyle().Foreground(components.ColorYellow).Render(logpointDotSymbol)
+	disabledLogpointDot = lipgloss.NewStyle().Foreground(components.ColorGrey).Render(logpointDotSymbol)
+
+	enabledTemporaryDot  = lipgloss.NewStyle().Foreground(components.ColorRed).Render(temporaryDotSymbol)
+	disabledTemporaryDot = lipgloss.NewStyle().Foreground(components.ColorGrey).Render(temporaryDotSymbol)
+	enabledDependentDot  = lipgloss.NewStyle().Foreground(components.ColorRed).Render(dependentDotSymbol)
+	disabledDependentDot = lipgloss.NewStyle().Foreground(components.ColorGrey).Render(dependentDotSymbol)
+
 	arrow             = lipgloss.NewStyle().Foreground(components.ColorGreen).Render(arrowSymbol)
 	arrowInBreakpoint = lipgloss.NewStyle().Foreground(components.ColorRed).Render(arrowSymbol)
 )
@@ -117,6 +128,20 @@ func (m viewportWithCursorModel) Update(msg tea.Msg) (viewportWithCursorModel, t
 		m.renderLine(msg.Line)
 		return m, nil
 
+	case messages.DebuggerBreakpointAmended:
+		if msg.Filename != m.filename {
+			return m, nil
+		}
+
+		bp, err := m.debugger.Breakpoint(msg.ID)
+		if err != nil {
+			return m, messages.ErrorCmd(fmt.Errorf("could not get breakpoints: %w", err))
+		}
+
+		m.breakpoints[msg.Line] = bp
+		m.renderLine(msg.Line)
+		return m, nil
+
 	case messages.DebuggerBreakpointToggled:
 		if msg.Filename != m.filename {
 			return m, nil
@@ -253,9 +278,22 @@ func (m *viewportWithCursorModel) formatLine(lineNumber int) (string, error) {
 			prefix = arrow
 		}
 	} else if isBpInLine {
-		if bp.Disabled {
+		switch {
+		case bp.LogMessage != "" && bp.Disabled:
+			prefix = disabledLogpointDot
+		case bp.LogMessage != "":
+			prefix = enabledLogpointDot
+		case bp.DependsOn != 0 && bp.Disabled:
+			prefix = disabledDependentDot
+		case bp.DependsOn != 0:
+			prefix = enabledDependentDot
+		case bp.Temporary && bp.Disabled:
+			prefix = disabledTemporaryDot
+		case bp.Temporary:
+			prefix = enabledTemporaryDot
+		case bp.Disabled:
 			prefix = disabledBreakpointDot
-		} else {
+		default:
 			prefix = enabledBreakpointDot
 		}
 	} else {
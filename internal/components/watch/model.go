@@ -0,0 +1,340 @@
+package watch
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/andersonjoseph/drill/internal/components"
+	"github.com/andersonjoseph/drill/internal/debugger"
+	"github.com/andersonjoseph/drill/internal/messages"
+	"github.com/andersonjoseph/drill/internal/paths"
+	"github.com/andersonjoseph/drill/internal/store"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/paginator"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const hintString = "a: add, e: edit, d: delete, f: change format, j: down, k: up"
+
+var (
+	noItemsStyle lipgloss.Style = lipgloss.NewStyle().Width(0).Foreground(components.ColorGrey)
+
+	paginatorStyleFocused lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorGreen).PaddingRight(2)
+	paginatorStyleDefault lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorWhite).PaddingRight(2)
+
+	exprStyleDefault lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorGrey)
+	exprStyleFocused lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorPurple).Bold(true)
+	valueStyle       lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorWhite)
+	changedStyle     lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorYellow).Bold(true)
+	errorStyle       lipgloss.Style = lipgloss.NewStyle().Foreground(components.ColorRed)
+
+	listItemStyle = lipgloss.NewStyle()
+)
+
+// watchExpression is an expression of the Watch window, expr may start with
+// a format verb like %x.
+type watchExpression struct {
+	expr     string
+	variable debugger.Variable
+	value    string
+	err      string
+	changed  bool
+}
+
+type Model struct {
+	ID              int
+	title           string
+	IsFocused       bool
+	width           int
+	height          int
+	list            list.Model
+	debugger        *debugger.Debugger
+	expressionInput expressionInputModel
+	projectRoot     string
+	expressions     []watchExpression
+	// editing is the index of the expression being edited, -1 when adding
+	editing int
+}
+
+func New(id int, d *debugger.Debugger) Model {
+	l := list.New([]list.Item{}, listDelegate{}, 0, 0)
+	l.SetShowHelp(false)
+	l.SetShowFilter(false)
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
+	l.Styles.PaginationStyle = paginatorStyleDefault
+	l.Styles.NoItems = lipgloss.NewStyle().Width(0)
+
+	p := paginator.New()
+	p.Type = paginator.Arabic
+	p.PerPage = 5
+	p.SetTotalPages(0)
+	p.ArabicFormat = lipgloss.NewStyle().
+		Margin(0).Padding(0).
+		Align(lipgloss.Right).
+		Render("%d of %d ")
+
+	l.Paginator = p
+
+	m := Model{
+		ID:              id,
+		title:           "Watch",
+		list:            l,
+		debugger:        d,
+		expressionInput: newExpressionInputModel(id),
+		projectRoot:     paths.GetProjectRoot(),
+		editing:         -1,
+	}
+
+	// the expressions can't be evaluated yet, RefreshContent does it
+	if project, err := store.Load(m.projectRoot); err == nil {
+		for _, expr := range project.Watches {
+			m.expressions = append(m.expressions, watchExpression{expr: expr})
+		}
+	}
+
+	return m
+}
+
+func (m Model) Init() tea.Cmd { return nil }
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case messages.WindowFocused:
+		m.IsFocused = int(msg) == m.ID
+		m.list.SetDelegate(listDelegate{parentFocused: m.IsFocused})
+
+		if !m.IsFocused {
+			m.list.Styles.PaginationStyle = paginatorStyleDefault
+			return m, nil
+		} else {
+			m.list.Styles.PaginationStyle = paginatorStyleFocused
+		}
+
+		return m, func() tea.Msg {
+			return messages.UpdatedHint(hintString)
+		}
+
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.width = msg.Width
+		m.list.SetHeight(msg.Height)
+		m.list.SetWidth(msg.Width)
+		m.list.Styles.NoItems = noItemsStyle.Width(msg.Width)
+
+		m.expressionInput, _ = m.expressionInput.Update(tea.WindowSizeMsg{Width: m.width})
+		return m, nil
+
+	case messages.RefreshContent, messages.DebuggerRestarted, messages.DebuggerStepped:
+		m.evaluate()
+		return m, nil
+
+	case messages.WatchExpressionAdded:
+		expr := strings.TrimSpace(string(msg))
+		if expr == "" || slices.ContainsFunc(m.expressions, func(w watchExpression) bool { return w.expr == expr }) {
+			return m, nil
+		}
+
+		w := watchExpression{expr: expr}
+		m.evaluateExpression(&w)
+		m.expressions = append(m.expressions, w)
+
+		m.list.SetItems(expressionsToListItems(m.expressions))
+		return m, m.saveExpressions()
+
+	case messageNewExpression:
+		expr := strings.TrimSpace(string(msg))
+		if m.editing < 0 || m.editing >= len(m.expressions) {
+			return m, messages.WatchExpressionAddedCmd(expr)
+		}
+
+		if expr == "" {
+			m.expressions = slices.Delete(m.expressions, m.editing, m.editing+1)
+		} else {
+			m.expressions[m.editing] = watchExpression{expr: expr}
+			m.evaluateExpression(&m.expressions[m.editing])
+		}
+		m.editing = -1
+
+		m.list.SetItems(expressionsToListItems(m.expressions))
+		return m, m.saveExpressions()
+
+	case tea.KeyMsg:
+		var cmd tea.Cmd
+		if m.expressionInput.isFocused {
+			m.expressionInput, cmd = m.expressionInput.Update(msg)
+			return m, cmd
+		}
+
+		if !m.IsFocused {
+			return m, nil
+		}
+
+		if msg.String() == "a" {
+			m.editing = -1
+			m.expressionInput.setFocus(true)
+			m.expressionInput.setContent("")
+			return m, func() tea.Msg {
+				return messages.TextInputFocused(true)
+			}
+		}
+
+		if msg.String() == "e" {
+			if m.list.SelectedItem() == nil {
+				return m, nil
+			}
+
+			m.editing = m.list.Index()
+			m.expressionInput.setFocus(true)
+			m.expressionInput.setContent(m.expressions[m.editing].expr)
+			return m, func() tea.Msg {
+				return messages.TextInputFocused(true)
+			}
+		}
+
+		if msg.String() == "f" {
+			if m.list.SelectedItem() == nil {
+				return m, nil
+			}
+
+			w := &m.expressions[m.list.Index()]
+			if w.err != "" {
+				return m, nil
+			}
+
+			format, expr := debugger.SplitFormat(w.expr)
+			format = debugger.NextFormat(w.variable, format)
+			w.expr = debugger.WithFormat(format, expr)
+			w.value = w.variable.Format(format).Value
+
+			m.list.SetItems(expressionsToListItems(m.expressions))
+			return m, m.saveExpressions()
+		}
+
+		if msg.String() == "d" {
+			if m.list.SelectedItem() == nil {
+				return m, nil
+			}
+
+			i := m.list.Index()
+			m.expressions = slices.Delete(m.expressions, i, i+1)
+			m.list.SetItems(expressionsToListItems(m.expressions))
+			return m, m.saveExpressions()
+		}
+
+		m.list, cmd = m.list.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m Model) View() string {
+	if m.expressionInput.isFocused {
+		return m.expressionInput.View()
+	}
+
+	return m.list.View()
+}
+
+// evaluate re-evaluates every expression, flagging the ones whose value
+// changed since the last stop.
+func (m *Model) evaluate() {
+	for i := range m.expressions {
+		m.evaluateExpression(&m.expressions[i])
+	}
+
+	m.list.SetItems(expressionsToListItems(m.expressions))
+}
+
+func (m *Model) evaluateExpression(w *watchExpression) {
+	oldValue, oldErr := w.value, w.err
+
+	format, expr := debugger.SplitFormat(w.expr)
+	v, err := m.debugger.EvalVariable(expr)
+	if err != nil {
+		w.variable, w.value, w.err = debugger.Variable{}, "", strings.TrimPrefix(err.Error(), "error evaluating expression: ")
+	} else {
+		w.variable, w.value, w.err = v, v.Format(format).Value, ""
+	}
+
+	// the first evaluation has nothing to compare with
+	hadValue := oldValue != "" || oldErr != ""
+	w.changed = hadValue && (w.value != oldValue || w.err != oldErr)
+}
+
+func (m Model) saveExpressions() tea.Cmd {
+	if m.projectRoot == "" {
+		return nil
+	}
+
+	watches := make([]string, len(m.expressions))
+	for i := range m.expressions {
+		watches[i] = m.expressions[i].expr
+	}
+
+	err := store.Update(m.projectRoot, func(p *store.Project) {
+		p.Watches = watches
+	})
+	if err != nil {
+		return messages.ErrorCmd(fmt.Errorf("error saving watch expressions: %w", err))
+	}
+
+	return nil
+}
+
+type listDelegate struct {
+	parentFocused bool
+}
+
+func (d listDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	listItem, ok := item.(listItem)
+	if !ok {
+		return
+	}
+
+	listItem.isFocused = m.Index() == index && d.parentFocused
+	fmt.Fprint(w, listItem.Render(m.Width()))
+}
+
+func (d listDelegate) Height() int                               { return 1 }
+func (d listDelegate) Spacing() int                              { return 0 }
+func (d listDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd { return nil }
+
+type listItem struct {
+	expression watchExpression
+	isFocused  bool
+}
+
+func (i listItem) FilterValue() string { return "" }
+func (i listItem) Render(width int) string {
+	exprStyle := exprStyleDefault
+	if i.isFocused {
+		exprStyle = exprStyleFocused
+	}
+
+	var value string
+	switch {
+	case i.expression.err != "":
+		value = errorStyle.Render(i.expression.err)
+	case i.expression.changed:
+		value = changedStyle.Render(i.expression.value)
+	default:
+		value = valueStyle.Render(i.expression.value)
+	}
+
+	return listItemStyle.
+		MaxWidth(width).
+		Render(exprStyle.Render(i.expression.expr+":"), value)
+}
+
+func expressionsToListItems(expressions []watchExpression) []list.Item {
+	items := make([]list.Item, len(expressions))
+	for i := range expressions {
+		items[i] = listItem{expression: expressions[i]}
+	}
+
+	return items
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// projectFile is the name of the config file read from the project root,
+// its settings take precedence over the user config.
+const projectFile = ".drill.json"
+
+// LoadConfig controls how much of a value is read from the debugged program,
+// see the LoadConfig of Delve's API.
+type LoadConfig struct {
+	FollowPointers     bool `json:"followPointers"`
+	MaxVariableRecurse int  `json:"maxVariableRecurse"`
+	MaxStringLen       int  `json:"maxStringLen"`
+	MaxArrayValues     int  `json:"maxArrayValues"`
+	MaxStructFields    int  `json:"maxStructFields"`
+}
+
+// Printer is a display rule for the values of a type. Template builds the
+// value from its fields, e.g. "{lo}-{hi}" or "{[0:4]:x}", and Expr is an
+// expression evaluated in the program where $ stands for the value, e.g.
+// "$.name". Type matches the full type name or its package.Name suffix.
+type Printer struct {
+	Type     string `json:"type"`
+	Template string `json:"template,omitempty"`
+	Expr     string `json:"expr,omitempty"`
+}
+
+type Config struct {
+	Load LoadConfig `json:"load"`
+	// Printers of the project config come after the user ones, the last
+	// rule matching a type is used.
+	Printers []Printer `json:"printers"`
+}
+
+func Default() Config {
+	return Config{
+		Load: LoadConfig{
+			FollowPointers:     true,
+			MaxVariableRecurse: 4,
+			MaxStringLen:       32,
+			MaxArrayValues:     32,
+			MaxStructFields:    32,
+		},
+	}
+}
+
+// Load reads the user config and then the project config on top of the
+// defaults. Settings missing from both keep their default value.
+func Load(projectRoot string) (Config, error) {
+	c := Default()
+
+	userPath, err := userConfigPath()
+	if err != nil {
+		return c, fmt.Errorf("error loading config: %w", err)
+	}
+
+	paths := []string{userPath}
+	if projectRoot != "" {
+		paths = append(paths, filepath.Join(projectRoot, projectFile))
+	}
+
+	var printers []Printer
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return c, fmt.Errorf("error loading config: %w", err)
+		}
+
+		if err := json.Unmarshal(content, &c); err != nil {
+			return c, fmt.Errorf("error loading config: %s: %w", path, err)
+		}
+
+		// unlike the other settings, printers add up instead of replacing
+		// the ones of the previous file
+		for _, p := range c.Printers {
+			if err := p.validate(); err != nil {
+				return c, fmt.Errorf("error loading config: %s: %w", path, err)
+			}
+		}
+		printers = append(printers, c.Printers...)
+		c.Printers = nil
+	}
+	c.Printers = printers
+
+	return c, nil
+}
+
+func (p Printer) validate() error {
+	if p.Type == "" {
+		return errors.New("printer without a type")
+	}
+	if (p.Template == "") == (p.Expr == "") {
+		return fmt.Errorf("printer for %s needs either a template or an expr", p.Type)
+	}
+
+	return nil
+}
+
+func userConfigPath() (string, error) {
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("error finding config directory: %w", err)
+		}
+		configDir = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(configDir, "drill", "config.json"), nil
+}
@@ -6,48 +6,151 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+	"reflect"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/andersonjoseph/drill/internal/config"
+	"github.com/andersonjoseph/drill/internal/scope"
 	"github.com/go-delve/delve/service/api"
 	"github.com/go-delve/delve/service/rpc2"
 )
 
 type Variable struct {
 	Name           string
+	Type           string
+	Kind           reflect.Kind
 	Value          string
 	MultilineValue string
+	// Expr evaluates to this variable in the scope it was loaded from.
+	Expr     string
+	Children []Variable
+	// Missing counts the elements, or bytes for strings, left out by the load
+	// limits. Unloaded is set when the children were not loaded because the
+	// variable is nested too deep.
+	Missing  int64
+	Unloaded bool
+
+	// raw is the value as Delve reads it, e.g. the digits of numbers and the
+	// unquoted content of strings, used to render the display formats
+	raw string
+}
+
+type WatchType uint8
+
+const (
+	WatchRead      = WatchType(api.WatchRead)
+	WatchWrite     = WatchType(api.WatchWrite)
+	WatchReadWrite = WatchRead | WatchWrite
+)
+
+func (w WatchType) String() string {
+	switch w {
+	case WatchRead:
+		return "r"
+	case WatchWrite:
+		return "w"
+	case WatchReadWrite:
+		return "rw"
+	default:
+		return ""
+	}
 }
 
 type Breakpoint struct {
-	ID        int
-	Name      string
-	Line      int
-	Filename  string
-	Disabled  bool
-	Condition string
+	ID         int
+	Name       string
+	Line       int
+	Filename   string
+	Disabled   bool
+	Condition  string
+	WatchExpr  string
+	WatchType  WatchType
+	LogMessage string
+	Temporary  bool
+	DependsOn  int
+	Group      string
+	// GoroutineID restricts the breakpoint to a goroutine, 0 means any.
+	GoroutineID int64
+
+	HitCondition  string
+	HitCondPerG   bool
+	TotalHitCount uint64
+	HitCounts     map[int64]uint64
+
+	CaptureOnHit bool
+}
+
+type BreakpointHit struct {
+	Breakpoint  Breakpoint
+	GoroutineID int64
+	Time        time.Time
+	Variables   []Variable
+	Arguments   []Variable
+	Locals      []Variable
+	Stack       []StackFrame
+}
+
+type DeferredCall struct {
+	FunctionName string
+	Filename     string
+	Line         int
+	Unreadable   string
 }
 
 type StackFrame struct {
 	Index        int
 	FunctionName string
+	Arguments    string
 	Filename     string
 	Line         int
+	Inlined      bool
+	Bottom       bool
+	Defers       []DeferredCall
 	Error        string
 }
 
-func newStackFrame(sf api.Stackframe, i int) StackFrame {
+func newStackFrame(sf api.Stackframe, i int, inlined bool) StackFrame {
+	args := make([]string, 0, len(sf.Arguments))
+	for _, arg := range sf.Arguments {
+		if arg.Flags&api.VariableReturnArgument != 0 {
+			continue
+		}
+		args = append(args, fmt.Sprintf("%s=%s", arg.Name, arg.SinglelineString()))
+	}
+
+	defers := make([]DeferredCall, len(sf.Defers))
+	for j, d := range sf.Defers {
+		defers[j] = DeferredCall{
+			FunctionName: d.DeferredLoc.Function.Name(),
+			Filename:     d.DeferredLoc.File,
+			Line:         d.DeferredLoc.Line,
+			Unreadable:   d.Unreadable,
+		}
+	}
+
 	return StackFrame{
 		Index:        i,
 		FunctionName: sf.Function.Name(),
+		Arguments:    strings.Join(args, ", "),
 		Filename:     sf.File,
 		Line:         sf.Line,
+		Inlined:      inlined,
+		Bottom:       sf.Bottom,
+		Defers:       defers,
 		Error:        sf.Err,
 	}
 }
 
+type Ancestor struct {
+	GoroutineID int64
+	Frames      []StackFrame
+	Unreadable  string
+}
+
 type outputSource int
 
 const (
@@ -62,25 +165,41 @@ type Output struct {
 	Source  outputSource
 }
 
+const captureStackDepth = 10
+
+// maxLoadAll caps the values and string bytes loaded when a value is loaded
+// in full.
+const maxLoadAll = 1 << 16
+
+// maxSnapLines is how far ResolveLine looks for a line with code.
+const maxSnapLines = 5
+
+// breakpointExtras holds the breakpoint settings drill implements on top of
+// delve, which has no field to keep them in.
+type breakpointExtras struct {
+	logMessage string
+	temporary  bool
+	dependsOn  int
+	group      string
+	goroutine  int64
+}
+
 type Debugger struct {
-	client  *rpc2.RPCClient
-	ready   chan string
-	Output  chan Output
-	lcfg    api.LoadConfig
-	isReady bool
+	client   *rpc2.RPCClient
+	ready    chan string
+	Output   chan Output
+	lcfg     api.LoadConfig
+	isReady  bool
+	extras   map[int]breakpointExtras
+	printers []config.Printer
 }
 
 func New(command, filename string) (*Debugger, error) {
 	d := &Debugger{
 		ready:  make(chan string),
 		Output: make(chan Output),
-		lcfg: api.LoadConfig{
-			FollowPointers:     true,
-			MaxVariableRecurse: 4,
-			MaxStringLen:       32,
-			MaxArrayValues:     32,
-			MaxStructFields:    32,
-		},
+		extras: make(map[int]breakpointExtras),
+		lcfg:   toAPILoadConfig(config.Default().Load),
 	}
 	if err := d.startProcess(command, filename); err != nil {
 		return nil, fmt.Errorf("error starting debugger process: %w", err)
@@ -89,6 +208,8 @@ func New(command, filename string) (*Debugger, error) {
 	select {
 	case addr := <-d.ready:
 		d.client = rpc2.NewClient(addr)
+		// function calls load their return values with the same limits
+		d.client.SetReturnValuesLoadConfig(&d.lcfg)
 	case <-time.After(time.Second * 10):
 		return nil, errors.New("timeout")
 	}
@@ -160,7 +281,7 @@ func (d Debugger) FileBreakpoints(filename string) (map[int]Breakpoint, error) {
 	}
 
 	for _, bp := range bps {
-		if bp.Filename != filename {
+		if bp.Filename != filename || bp.WatchExpr != "" {
 			continue
 		}
 		bpsInThisFile[bp.Line] = bp
@@ -175,7 +296,33 @@ func (d Debugger) Breakpoint(id int) (Breakpoint, error) {
 		return Breakpoint{}, fmt.Errorf("error getting breakpoint: %w", err)
 	}
 
-	return apiBpToInternalBp(*bp), nil
+	return d.apiBpToInternalBp(*bp), nil
+}
+
+// SetLoadConfig changes how much of a value is loaded by the variables,
+// print and call stack requests that follow.
+func (d *Debugger) SetLoadConfig(cfg config.LoadConfig) {
+	d.lcfg = toAPILoadConfig(cfg)
+}
+
+func (d Debugger) LoadConfig() config.LoadConfig {
+	return config.LoadConfig{
+		FollowPointers:     d.lcfg.FollowPointers,
+		MaxVariableRecurse: d.lcfg.MaxVariableRecurse,
+		MaxStringLen:       d.lcfg.MaxStringLen,
+		MaxArrayValues:     d.lcfg.MaxArrayValues,
+		MaxStructFields:    d.lcfg.MaxStructFields,
+	}
+}
+
+func toAPILoadConfig(cfg config.LoadConfig) api.LoadConfig {
+	return api.LoadConfig{
+		FollowPointers:     cfg.FollowPointers,
+		MaxVariableRecurse: cfg.MaxVariableRecurse,
+		MaxStringLen:       cfg.MaxStringLen,
+		MaxArrayValues:     cfg.MaxArrayValues,
+		MaxStructFields:    cfg.MaxStructFields,
+	}
 }
 
 func (d Debugger) LocalVariables() ([]Variable, error) {
@@ -201,22 +348,79 @@ func (d Debugger) LocalVariables() ([]Variable, error) {
 
 	localVariables := make([]Variable, len(vars))
 	for i := range vars {
-		localVariables[i] = apiVarToInternalVar(vars[i])
+		localVariables[i] = d.applyPrinters(scope, apiVarToInternalVar(vars[i]))
 	}
 
 	return localVariables, nil
 }
 
-func (d Debugger) CallStack() ([]StackFrame, error) {
+// PackageVariables returns the package level variables whose full name,
+// e.g. main.counter, matches the filter regular expression.
+func (d Debugger) PackageVariables(filter string) ([]Variable, error) {
+	vars, err := d.client.ListPackageVariables(filter, d.lcfg)
+	if err != nil {
+		return nil, fmt.Errorf("error listing package variables: %w", err)
+	}
+
+	variables := make([]Variable, len(vars))
+	for i := range vars {
+		variables[i] = d.applyPrinters(api.EvalScope{GoroutineID: -1}, apiVarToInternalVar(vars[i]))
+	}
+
+	return variables, nil
+}
+
+// CurrentFunction returns the name of the function the current goroutine is
+// stopped in.
+func (d Debugger) CurrentFunction() (string, error) {
+	state, err := d.client.GetState()
+	if err != nil {
+		return "", fmt.Errorf("error getting current state: %w", err)
+	}
+	if state.CurrentThread == nil || state.CurrentThread.Function == nil {
+		return "", errors.New("error getting current function: the program is not stopped")
+	}
+
+	return state.CurrentThread.Function.Name(), nil
+}
+
+// CurrentPackage returns the import path of the package of the function the
+// current goroutine is stopped in.
+func (d Debugger) CurrentPackage() (string, error) {
+	fn, err := d.CurrentFunction()
+	if err != nil {
+		return "", err
+	}
+
+	return packageName(fn), nil
+}
+
+// packageName returns the package of a function name like
+// github.com/user/pkg.(*T).Method.
+func packageName(fn string) string {
+	if i := strings.Index(fn, "["); i >= 0 {
+		fn = fn[:i]
+	}
+
+	slash := strings.LastIndex(fn, "/")
+	if dot := strings.Index(fn[slash+1:], "."); dot >= 0 {
+		return fn[:slash+1+dot]
+	}
+
+	return fn
+}
+
+func (d Debugger) CallStack(depth int) ([]StackFrame, error) {
 	state, err := d.client.GetState()
 	if err != nil {
 		return nil, fmt.Errorf("error getting call stack: debugger state: %w", err)
 	}
 
+	lcfg := d.lcfg
 	stack, err := d.client.Stacktrace(
 		state.CurrentThread.GoroutineID,
-		50, api.StacktraceSimple,
-		&api.LoadConfig{MaxStringLen: 64, MaxStructFields: 3},
+		depth, api.StacktraceSimple|api.StacktraceReadDefers,
+		&lcfg,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error getting call stack: stacktrace: %w", err)
@@ -225,12 +429,49 @@ func (d Debugger) CallStack() ([]StackFrame, error) {
 	frames := make([]StackFrame, len(stack))
 
 	for i := len(stack) - 1; i >= 0; i-- {
-		frames[i] = newStackFrame(stack[i], i)
+		// an inlined call shares the frame of the function it was inlined into
+		inlined := i+1 < len(stack) && stack[i].FrameOffset == stack[i+1].FrameOffset
+		frames[i] = newStackFrame(stack[i], i, inlined)
 	}
 
 	return frames, nil
 }
 
+func (d Debugger) Ancestors(count, depth int) ([]Ancestor, error) {
+	state, err := d.client.GetState()
+	if err != nil {
+		return nil, fmt.Errorf("error getting goroutine ancestors: debugger state: %w", err)
+	}
+	if state.CurrentThread == nil {
+		return nil, nil
+	}
+
+	ancestors, err := d.client.Ancestors(state.CurrentThread.GoroutineID, count, depth)
+	if err != nil {
+		// the target was not started with GODEBUG=tracebackancestors=N
+		if strings.Contains(err.Error(), "tracebackancestors is disabled") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting goroutine ancestors: %w", err)
+	}
+
+	result := make([]Ancestor, len(ancestors))
+	for i, a := range ancestors {
+		frames := make([]StackFrame, len(a.Stack))
+		for j := range a.Stack {
+			frames[j] = newStackFrame(a.Stack[j], j, false)
+		}
+
+		result[i] = Ancestor{
+			GoroutineID: a.ID,
+			Frames:      frames,
+			Unreadable:  a.Unreadable,
+		}
+	}
+
+	return result, nil
+}
+
 func (d Debugger) Breakpoints() ([]Breakpoint, error) {
 	bps, err := d.client.ListBreakpoints(false)
 	if err != nil {
@@ -240,7 +481,7 @@ func (d Debugger) Breakpoints() ([]Breakpoint, error) {
 
 	breakpoints := make([]Breakpoint, len(bps))
 	for i := range bps {
-		breakpoints[i] = apiBpToInternalBp(*bps[i])
+		breakpoints[i] = d.apiBpToInternalBp(*bps[i])
 	}
 
 	return breakpoints, nil
@@ -255,7 +496,146 @@ func (d Debugger) CreateBreakpoint(filename string, line int) (Breakpoint, error
 		return Breakpoint{}, fmt.Errorf("error creating breakpoint: %w", err)
 	}
 
-	return apiBpToInternalBp(*bp), nil
+	return d.apiBpToInternalBp(*bp), nil
+}
+
+// CreateBreakpointNear creates a breakpoint at line or, when there is no code
+// there, at the next line that has some.
+func (d Debugger) CreateBreakpointNear(filename string, line int) (Breakpoint, error) {
+	resolved, err := d.ResolveLine(filename, line)
+	if err != nil {
+		return Breakpoint{}, fmt.Errorf("error creating breakpoint: %w", err)
+	}
+
+	return d.CreateBreakpoint(filename, resolved)
+}
+
+// ResolveLine returns the first line at or after line where a breakpoint can
+// be set. The search doesn't go past the end of the function enclosing line,
+// the next function's code would stop somewhere else entirely.
+func (d Debugger) ResolveLine(filename string, line int) (int, error) {
+	last := line + maxSnapLines - 1
+	if end, ok, err := scope.FunctionEnd(filename, line); err == nil && ok {
+		last = min(last, end)
+	}
+
+	evalScope := api.EvalScope{GoroutineID: -1}
+	for l := line; l <= last; l++ {
+		locs, _, err := d.client.FindLocation(evalScope, fmt.Sprintf("%s:%d", filename, l), true, nil)
+		if err != nil || !slices.ContainsFunc(locs, hasCode) {
+			continue
+		}
+
+		return l, nil
+	}
+
+	return 0, fmt.Errorf("no code found at or after %s:%d", filename, line)
+}
+
+// hasCode reports whether loc has instructions, Delve also returns locations
+// for blank and comment lines when asked for non executable lines.
+func hasCode(loc api.Location) bool {
+	return loc.PC != 0 || len(loc.PCs) > 0
+}
+
+// LocationLines returns the file and line of the places location, in any
+// form CreateBreakpointsAt accepts, resolves to.
+func (d Debugger) LocationLines(location string) ([]string, error) {
+	locs, _, err := d.findLocation(location)
+	if err != nil {
+		return nil, fmt.Errorf("error finding location %q: %w", location, err)
+	}
+
+	lines := make([]string, len(locs))
+	for i, loc := range locs {
+		lines[i] = fmt.Sprintf("%s:%d", loc.File, loc.Line)
+	}
+
+	return lines, nil
+}
+
+func (d Debugger) findLocation(location string) ([]api.Location, string, error) {
+	scope := api.EvalScope{GoroutineID: -1}
+	if state, err := d.client.GetState(); err == nil && state.CurrentThread != nil {
+		scope.GoroutineID = state.CurrentThread.GoroutineID
+	}
+
+	return d.client.FindLocation(scope, location, true, nil)
+}
+
+func (d Debugger) CreateBreakpointsAt(location string) ([]Breakpoint, error) {
+	locs, substituted, err := d.findLocation(location)
+	if err != nil {
+		return nil, fmt.Errorf("error creating breakpoint: finding location %q: %w", location, err)
+	}
+	if substituted != "" {
+		location = substituted
+	}
+
+	breakpoints := make([]Breakpoint, 0, len(locs))
+	for _, loc := range locs {
+		bp, err := d.client.CreateBreakpointWithExpr(&api.Breakpoint{
+			Addr:    loc.PC,
+			Addrs:   loc.PCs,
+			AddrPid: loc.PCPids,
+		}, location, nil, false)
+		if err != nil {
+			return breakpoints, fmt.Errorf("error creating breakpoint at %s:%d: %w", loc.File, loc.Line, err)
+		}
+
+		breakpoints = append(breakpoints, d.apiBpToInternalBp(*bp))
+	}
+
+	return breakpoints, nil
+}
+
+func (d Debugger) CreateWatchpoint(expr string, wtype WatchType) (Breakpoint, error) {
+	state, err := d.client.GetState()
+	if err != nil {
+		return Breakpoint{}, fmt.Errorf("error creating watchpoint: debugger state: %w", err)
+	}
+
+	scope := api.EvalScope{
+		GoroutineID: state.CurrentThread.GoroutineID,
+	}
+
+	v, err := d.client.EvalVariable(scope, expr, d.lcfg)
+	if err != nil {
+		return Breakpoint{}, fmt.Errorf("error creating watchpoint: evaluating expression: %w", err)
+	}
+
+	bp, err := d.client.CreateWatchpoint(scope, expr, api.WatchType(wtype))
+	if err != nil {
+		return Breakpoint{}, fmt.Errorf("error creating watchpoint: %w", err)
+	}
+
+	// the watched expression may not be in scope where the watchpoint is hit,
+	// so its value is read back through the watched address instead
+	bp.Variables = []string{addressExpr(v.Type, v.Addr)}
+	if err := d.client.AmendBreakpoint(bp); err != nil {
+		return Breakpoint{}, fmt.Errorf("error creating watchpoint: amend breakpoint: %w", err)
+	}
+
+	return d.apiBpToInternalBp(*bp), nil
+}
+
+func (d Debugger) WatchpointValue(id int) (Variable, error) {
+	bp, err := d.client.GetBreakpoint(id)
+	if err != nil {
+		return Variable{}, fmt.Errorf("error getting watchpoint value: getting breakpoint: %w", err)
+	}
+
+	if len(bp.Variables) == 0 {
+		return Variable{}, fmt.Errorf("error getting watchpoint value: %d is not a watchpoint", id)
+	}
+
+	v, err := d.EvalVariable(bp.Variables[0])
+	if err != nil {
+		return Variable{}, fmt.Errorf("error getting watchpoint value: %w", err)
+	}
+	v.Name = bp.WatchExpr
+
+	return v, nil
 }
 
 func (d Debugger) CreateBreakpointNow() (Breakpoint, error) {
@@ -273,14 +653,149 @@ func (d Debugger) AddConditionToBreakpoint(id int, cond string) (Breakpoint, err
 		return Breakpoint{}, fmt.Errorf("error adding breakpoint condition: getting breakpoint: %w", err)
 	}
 
-	bp.Cond = cond
+	bp.Cond = goroutineCondition(cond, d.extras[id].goroutine)
 
 	err = d.client.AmendBreakpoint(bp)
 	if err != nil {
 		return Breakpoint{}, fmt.Errorf("error adding condition to breakpoint: amend breakpoint: %w", err)
 	}
 
-	return apiBpToInternalBp(*bp), nil
+	return d.apiBpToInternalBp(*bp), nil
+}
+
+// SetBreakpointGoroutine restricts breakpoint id to the goroutine with the
+// given ID, 0 lifts the restriction.
+func (d Debugger) SetBreakpointGoroutine(id int, goroutineID int64) (Breakpoint, error) {
+	bp, err := d.client.GetBreakpoint(id)
+	if err != nil {
+		return Breakpoint{}, fmt.Errorf("error setting breakpoint goroutine: getting breakpoint: %w", err)
+	}
+
+	extras := d.extras[id]
+	bp.Cond = goroutineCondition(userCondition(bp.Cond, extras.goroutine), goroutineID)
+
+	if err := d.client.AmendBreakpoint(bp); err != nil {
+		return Breakpoint{}, fmt.Errorf("error setting breakpoint goroutine: amend breakpoint: %w", err)
+	}
+
+	extras.goroutine = goroutineID
+	d.extras[id] = extras
+
+	return d.apiBpToInternalBp(*bp), nil
+}
+
+func (d Debugger) CurrentGoroutineID() (int64, error) {
+	state, err := d.client.GetState()
+	if err != nil {
+		return 0, fmt.Errorf("error getting current goroutine: %w", err)
+	}
+	if state.CurrentThread == nil {
+		return 0, errors.New("error getting current goroutine: the program is not stopped")
+	}
+
+	return state.CurrentThread.GoroutineID, nil
+}
+
+// goroutineCondition prefixes cond with a check on the goroutine ID, the same
+// way Delve restricts its internal breakpoints to a goroutine.
+func goroutineCondition(cond string, goroutineID int64) string {
+	if goroutineID == 0 {
+		return cond
+	}
+
+	goroutineCond := fmt.Sprintf("runtime.curg.goid == %d", goroutineID)
+	if cond == "" {
+		return goroutineCond
+	}
+
+	return fmt.Sprintf("%s && (%s)", goroutineCond, cond)
+}
+
+// userCondition strips the goroutine check added by goroutineCondition.
+func userCondition(cond string, goroutineID int64) string {
+	if goroutineID == 0 {
+		return cond
+	}
+
+	rest, found := strings.CutPrefix(cond, fmt.Sprintf("runtime.curg.goid == %d", goroutineID))
+	if !found {
+		return cond
+	}
+	if rest == "" {
+		return ""
+	}
+
+	if user, found := strings.CutPrefix(rest, " && ("); found && strings.HasSuffix(user, ")") {
+		return strings.TrimSuffix(user, ")")
+	}
+
+	return cond
+}
+
+// ValidateCondition checks cond can be used as the condition of breakpoint
+// id. When the program is stopped at the breakpoint Delve evaluates the
+// condition there and an error means it is invalid. Otherwise the condition is
+// only checked against the names visible in the source, which doesn't know
+// every expression Delve accepts, so problems found that way are returned as
+// a warning.
+func (d Debugger) ValidateCondition(id int, cond string) (string, error) {
+	bp, err := d.client.GetBreakpoint(id)
+	if err != nil {
+		return "", fmt.Errorf("error validating condition: getting breakpoint: %w", err)
+	}
+
+	if evalScope, ok := d.breakpointScope(bp); ok {
+		v, err := d.client.EvalVariable(evalScope, cond, d.lcfg)
+		if err != nil {
+			return "", fmt.Errorf("invalid condition: %w", err)
+		}
+		if v.Kind != reflect.Bool {
+			return "", fmt.Errorf("invalid condition: %s is a %s, not a bool", cond, v.Type)
+		}
+		return "", nil
+	}
+
+	undefined, err := scope.Undefined(cond, bp.File, bp.Line)
+	if err != nil {
+		return fmt.Sprintf("condition %q could not be checked: %s", cond, err), nil
+	}
+	if len(undefined) > 0 {
+		return fmt.Sprintf("condition %q may not evaluate, not found in scope: %s", cond, strings.Join(undefined, ", ")), nil
+	}
+
+	return "", nil
+}
+
+// VisibleNames returns the names a condition on breakpoint id can reference.
+func (d Debugger) VisibleNames(id int) ([]string, error) {
+	bp, err := d.client.GetBreakpoint(id)
+	if err != nil {
+		return nil, fmt.Errorf("error listing visible names: getting breakpoint: %w", err)
+	}
+
+	return scope.VisibleNames(bp.File, bp.Line)
+}
+
+// breakpointScope returns the scope of the frame stopped at bp, if any.
+func (d Debugger) breakpointScope(bp *api.Breakpoint) (api.EvalScope, bool) {
+	state, err := d.client.GetState()
+	if err != nil || state.Exited || state.CurrentThread == nil {
+		return api.EvalScope{}, false
+	}
+
+	goroutineID := state.CurrentThread.GoroutineID
+	frames, err := d.client.Stacktrace(goroutineID, captureStackDepth, 0, nil)
+	if err != nil {
+		return api.EvalScope{}, false
+	}
+
+	for i, frame := range frames {
+		if frame.File == bp.File && frame.Line == bp.Line {
+			return api.EvalScope{GoroutineID: goroutineID, Frame: i}, true
+		}
+	}
+
+	return api.EvalScope{}, false
 }
 
 func (d Debugger) AddAliasToBreakpoint(id int, alias string) (Breakpoint, error) {
@@ -295,7 +810,208 @@ func (d Debugger) AddAliasToBreakpoint(id int, alias string) (Breakpoint, error)
 		return Breakpoint{}, fmt.Errorf("error adding alias to breakpoint: amend breakpoint: %w", err)
 	}
 
-	return apiBpToInternalBp(*bp), nil
+	return d.apiBpToInternalBp(*bp), nil
+}
+
+func (d Debugger) SetBreakpointHitCondition(id int, cond string, perG bool) (Breakpoint, error) {
+	bp, err := d.client.GetBreakpoint(id)
+	if err != nil {
+		return Breakpoint{}, fmt.Errorf("error setting hit condition: getting breakpoint: %w", err)
+	}
+
+	bp.HitCond = cond
+	bp.HitCondPerG = perG
+
+	err = d.client.AmendBreakpoint(bp)
+	if err != nil {
+		return Breakpoint{}, fmt.Errorf("error setting hit condition: amend breakpoint: %w", err)
+	}
+
+	return d.apiBpToInternalBp(*bp), nil
+}
+
+func (d Debugger) SetBreakpointCapture(id int, capture bool) (Breakpoint, error) {
+	bp, err := d.client.GetBreakpoint(id)
+	if err != nil {
+		return Breakpoint{}, fmt.Errorf("error setting breakpoint capture: getting breakpoint: %w", err)
+	}
+
+	if capture {
+		lcfg := d.lcfg
+		bp.Stacktrace = captureStackDepth
+		bp.LoadArgs = &lcfg
+		bp.LoadLocals = &lcfg
+	} else {
+		bp.Stacktrace = 0
+		bp.LoadArgs = nil
+		bp.LoadLocals = nil
+	}
+
+	err = d.client.AmendBreakpoint(bp)
+	if err != nil {
+		return Breakpoint{}, fmt.Errorf("error setting breakpoint capture: amend breakpoint: %w", err)
+	}
+
+	return d.apiBpToInternalBp(*bp), nil
+}
+
+// SetBreakpointLogMessage turns the breakpoint into a logpoint, or back into a
+// breakpoint when message is empty. Delve resumes through logpoints without
+// stopping, so temporary breakpoints and prerequisites can't log messages:
+// they are only handled once the program stops.
+func (d Debugger) SetBreakpointLogMessage(id int, message string) (Breakpoint, error) {
+	bp, err := d.client.GetBreakpoint(id)
+	if err != nil {
+		return Breakpoint{}, fmt.Errorf("error setting log message: getting breakpoint: %w", err)
+	}
+
+	if bp.WatchExpr != "" {
+		return Breakpoint{}, errors.New("error setting log message: watchpoints can't log messages")
+	}
+	if message != "" && d.extras[id].temporary {
+		return Breakpoint{}, errors.New("error setting log message: temporary breakpoints can't log messages")
+	}
+	if message != "" && d.isPrerequisite(id) {
+		return Breakpoint{}, errors.New("error setting log message: breakpoints others depend on can't log messages")
+	}
+
+	bp.Tracepoint = message != ""
+	bp.Variables = logExpressions(message)
+
+	err = d.client.AmendBreakpoint(bp)
+	if err != nil {
+		return Breakpoint{}, fmt.Errorf("error setting log message: amend breakpoint: %w", err)
+	}
+
+	extras := d.extras[id]
+	extras.logMessage = message
+	d.extras[id] = extras
+
+	return d.apiBpToInternalBp(*bp), nil
+}
+
+func (d Debugger) SetBreakpointTemporary(id int, temporary bool) (Breakpoint, error) {
+	bp, err := d.client.GetBreakpoint(id)
+	if err != nil {
+		return Breakpoint{}, fmt.Errorf("error setting temporary breakpoint: getting breakpoint: %w", err)
+	}
+
+	// the program never stops at a logpoint to delete it
+	if temporary && d.extras[id].logMessage != "" {
+		return Breakpoint{}, errors.New("error setting temporary breakpoint: logpoints can't be temporary")
+	}
+
+	extras := d.extras[id]
+	extras.temporary = temporary
+	d.extras[id] = extras
+
+	return d.apiBpToInternalBp(*bp), nil
+}
+
+func (d Debugger) SetBreakpointGroup(id int, group string) (Breakpoint, error) {
+	bp, err := d.client.GetBreakpoint(id)
+	if err != nil {
+		return Breakpoint{}, fmt.Errorf("error setting breakpoint group: getting breakpoint: %w", err)
+	}
+
+	extras := d.extras[id]
+	extras.group = strings.TrimSpace(group)
+	d.extras[id] = extras
+
+	return d.apiBpToInternalBp(*bp), nil
+}
+
+func (d Debugger) SetBreakpointDependency(id int, dependsOn int) (Breakpoint, error) {
+	if id == dependsOn {
+		return Breakpoint{}, errors.New("error setting breakpoint dependency: a breakpoint can't depend on itself")
+	}
+
+	bp, err := d.client.GetBreakpoint(id)
+	if err != nil {
+		return Breakpoint{}, fmt.Errorf("error setting breakpoint dependency: getting breakpoint: %w", err)
+	}
+
+	if dependsOn != 0 {
+		if _, err := d.client.GetBreakpoint(dependsOn); err != nil {
+			return Breakpoint{}, fmt.Errorf("error setting breakpoint dependency: getting breakpoint %d: %w", dependsOn, err)
+		}
+		// the program never stops at a logpoint to enable its dependents
+		if d.extras[dependsOn].logMessage != "" {
+			return Breakpoint{}, errors.New("error setting breakpoint dependency: breakpoints can't depend on a logpoint")
+		}
+
+		// a dependent breakpoint stays disabled until the one it depends on is hit
+		bp.Disabled = true
+		if err := d.client.AmendBreakpoint(bp); err != nil {
+			return Breakpoint{}, fmt.Errorf("error setting breakpoint dependency: amend breakpoint: %w", err)
+		}
+	}
+
+	extras := d.extras[id]
+	extras.dependsOn = dependsOn
+	d.extras[id] = extras
+
+	return d.apiBpToInternalBp(*bp), nil
+}
+
+// isPrerequisite reports whether another breakpoint depends on id.
+func (d Debugger) isPrerequisite(id int) bool {
+	for _, extras := range d.extras {
+		if extras.dependsOn == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EnableDependentBreakpoints enables the disabled breakpoints waiting for id
+// to be hit and returns them.
+func (d Debugger) EnableDependentBreakpoints(id int) ([]Breakpoint, error) {
+	var enabled []Breakpoint
+
+	for dependentID, extras := range d.extras {
+		if extras.dependsOn != id {
+			continue
+		}
+
+		bp, err := d.client.GetBreakpoint(dependentID)
+		if err != nil {
+			return enabled, fmt.Errorf("error enabling dependent breakpoint: getting breakpoint: %w", err)
+		}
+		if !bp.Disabled {
+			continue
+		}
+
+		bp.Disabled = false
+		if err := d.client.AmendBreakpoint(bp); err != nil {
+			return enabled, fmt.Errorf("error enabling dependent breakpoint: amend breakpoint: %w", err)
+		}
+
+		enabled = append(enabled, d.apiBpToInternalBp(*bp))
+	}
+
+	return enabled, nil
+}
+
+func (d Debugger) disableDependentBreakpoints() error {
+	for id, extras := range d.extras {
+		if extras.dependsOn == 0 {
+			continue
+		}
+
+		bp, err := d.client.GetBreakpoint(id)
+		if err != nil {
+			return fmt.Errorf("error disabling dependent breakpoint: getting breakpoint: %w", err)
+		}
+
+		bp.Disabled = true
+		if err := d.client.AmendBreakpoint(bp); err != nil {
+			return fmt.Errorf("error disabling dependent breakpoint: amend breakpoint: %w", err)
+		}
+	}
+
+	return nil
 }
 
 func (d Debugger) ToggleBreakpoint(id int) error {
@@ -312,22 +1028,61 @@ func (d Debugger) ClearBreakpoint(id int) error {
 	if err != nil {
 		return fmt.Errorf("error clearing breakpoint: %w", err)
 	}
+	delete(d.extras, id)
 
 	return nil
 }
 
-func (d Debugger) Next() error {
-	_, err := d.client.Next()
-
+// Next steps over the current line. Like the other stepping commands, it
+// returns the breakpoints hit on the way.
+func (d Debugger) Next() ([]BreakpointHit, error) {
+	state, err := d.client.Next()
 	if err != nil {
-		return fmt.Errorf("error stepping over: %w", err)
+		return nil, fmt.Errorf("error stepping over: %w", err)
 	}
 
-	return nil
+	return d.stateHits(state), nil
 }
 
-func (d Debugger) Continue() {
-	<-d.client.Continue()
+func (d Debugger) Continue() []BreakpointHit {
+	var hits []BreakpointHit
+
+	for state := range d.client.Continue() {
+		hits = append(hits, d.stateHits(state)...)
+	}
+
+	return hits
+}
+
+// stateHits returns the breakpoints the threads of state are stopped at.
+func (d Debugger) stateHits(state *api.DebuggerState) []BreakpointHit {
+	if state == nil {
+		return nil
+	}
+
+	var hits []BreakpointHit
+	for _, th := range state.Threads {
+		if th.Breakpoint == nil {
+			continue
+		}
+
+		hit := BreakpointHit{
+			Breakpoint:  d.apiBpToInternalBp(*th.Breakpoint),
+			GoroutineID: th.GoroutineID,
+			Time:        time.Now(),
+		}
+		if info := th.BreakpointInfo; info != nil {
+			hit.Variables = apiVarsToInternalVars(info.Variables)
+			hit.Arguments = apiVarsToInternalVars(info.Arguments)
+			hit.Locals = apiVarsToInternalVars(info.Locals)
+			for i := range info.Stacktrace {
+				hit.Stack = append(hit.Stack, newStackFrame(info.Stacktrace[i], i, false))
+			}
+		}
+		hits = append(hits, hit)
+	}
+
+	return hits
 }
 
 func (d Debugger) Restart() error {
@@ -337,6 +1092,40 @@ func (d Debugger) Restart() error {
 		return fmt.Errorf("error restarting process: %w", err)
 	}
 
+	if err := d.disableDependentBreakpoints(); err != nil {
+		return fmt.Errorf("error restarting process: %w", err)
+	}
+
+	if err := d.clearGoroutineRestrictions(); err != nil {
+		return fmt.Errorf("error restarting process: %w", err)
+	}
+
+	return nil
+}
+
+// clearGoroutineRestrictions lifts the restrictions set with
+// SetBreakpointGoroutine, the IDs of the goroutines they name are given to
+// other goroutines on a new run.
+func (d Debugger) clearGoroutineRestrictions() error {
+	for id, extras := range d.extras {
+		if extras.goroutine == 0 {
+			continue
+		}
+
+		bp, err := d.client.GetBreakpoint(id)
+		if err != nil {
+			return fmt.Errorf("error clearing breakpoint goroutine: getting breakpoint: %w", err)
+		}
+
+		bp.Cond = userCondition(bp.Cond, extras.goroutine)
+		if err := d.client.AmendBreakpoint(bp); err != nil {
+			return fmt.Errorf("error clearing breakpoint goroutine: amend breakpoint: %w", err)
+		}
+
+		extras.goroutine = 0
+		d.extras[id] = extras
+	}
+
 	return nil
 }
 
@@ -353,18 +1142,20 @@ func (d Debugger) CurrentFile() (string, int, error) {
 	return state.CurrentThread.File, state.CurrentThread.Line, nil
 }
 
-func (d Debugger) StepIn() error {
-	if _, err := d.client.Step(); err != nil {
-		return fmt.Errorf("error stepping in: %w", err)
+func (d Debugger) StepIn() ([]BreakpointHit, error) {
+	state, err := d.client.Step()
+	if err != nil {
+		return nil, fmt.Errorf("error stepping in: %w", err)
 	}
-	return nil
+	return d.stateHits(state), nil
 }
 
-func (d Debugger) StepOut() error {
-	if _, err := d.client.StepOut(); err != nil {
-		return fmt.Errorf("error stepping out: %w", err)
+func (d Debugger) StepOut() ([]BreakpointHit, error) {
+	state, err := d.client.StepOut()
+	if err != nil {
+		return nil, fmt.Errorf("error stepping out: %w", err)
 	}
-	return nil
+	return d.stateHits(state), nil
 }
 
 func (d Debugger) EvalVariable(expr string) (variable Variable, err error) {
@@ -372,6 +1163,9 @@ func (d Debugger) EvalVariable(expr string) (variable Variable, err error) {
 	if err != nil {
 		return variable, fmt.Errorf("error getting current state: %w", err)
 	}
+	if state.CurrentThread == nil {
+		return variable, errors.New("error evaluating expression: the program is not stopped")
+	}
 
 	scope := api.EvalScope{
 		GoroutineID: state.CurrentThread.GoroutineID,
@@ -382,28 +1176,326 @@ func (d Debugger) EvalVariable(expr string) (variable Variable, err error) {
 		return variable, fmt.Errorf("error evaluating expression: %w", err)
 	}
 
-	return apiVarToInternalVar(*v), nil
+	return d.applyPrinters(scope, apiVarToInternalVar(*v)), nil
+}
+
+// ErrCallInterrupted is returned by Call when the called function stopped at
+// a breakpoint before returning, along with the breakpoints hit.
+var ErrCallInterrupted = errors.New("error calling function: the call stopped before returning")
+
+// Call runs expr, a function call, in the current goroutine and returns the
+// values the function returned.
+func (d Debugger) Call(expr string) ([]Variable, []BreakpointHit, error) {
+	state, err := d.client.GetState()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting current state: %w", err)
+	}
+	if state.CurrentThread == nil {
+		return nil, nil, errors.New("error calling function: the program is not stopped")
+	}
+
+	state, err = d.client.Call(state.CurrentThread.GoroutineID, expr, false)
+	if err != nil {
+		return nil, nil, callError(err)
+	}
+	if state.Exited {
+		return nil, nil, fmt.Errorf("error calling function: the program exited with status %d", state.ExitStatus)
+	}
+	if state.CurrentThread == nil || !state.CurrentThread.CallReturn {
+		return nil, d.stateHits(state), ErrCallInterrupted
+	}
+
+	values := make([]Variable, len(state.CurrentThread.ReturnValues))
+	for i, v := range state.CurrentThread.ReturnValues {
+		values[i] = d.applyPrinters(api.EvalScope{GoroutineID: -1}, apiVarToInternalVar(v))
+	}
+
+	return values, nil, nil
+}
+
+// callError explains the errors of calls that can't be injected where the
+// goroutine is stopped, they are the most common and the least obvious.
+func callError(err error) error {
+	msg := err.Error()
+	if strings.Contains(msg, "not at safe point") ||
+		strings.Contains(msg, "call from") ||
+		strings.Contains(msg, "on a locked thread") {
+		return fmt.Errorf("error calling function: %w (calls can't be injected where the goroutine is stopped, step to another line and retry)", err)
+	}
+
+	if strings.Contains(msg, "not supported") {
+		return fmt.Errorf("error calling function: %w (function calls need Go 1.11 or later and are not available in core dumps)", err)
+	}
+
+	return fmt.Errorf("error calling function: %w", err)
+}
+
+// SetVariable assigns value to the variable expr refers to, in the scope of
+// the current goroutine.
+func (d Debugger) SetVariable(expr string, value string) error {
+	state, err := d.client.GetState()
+	if err != nil {
+		return fmt.Errorf("error getting current state: %w", err)
+	}
+	if state.CurrentThread == nil {
+		return errors.New("error setting variable: the program is not stopped")
+	}
+
+	scope := api.EvalScope{
+		GoroutineID: state.CurrentThread.GoroutineID,
+	}
+
+	if err := d.client.SetVariable(scope, expr, value); err != nil {
+		return fmt.Errorf("error setting variable: %w", err)
+	}
+
+	return nil
 }
 
-func apiBpToInternalBp(bp api.Breakpoint) Breakpoint {
+func (d Debugger) apiBpToInternalBp(bp api.Breakpoint) Breakpoint {
 	if bp.Name == "" {
 		bp.Name = fmt.Sprintf("%s:%d", bp.File, bp.Line)
 	}
+	if bp.WatchExpr != "" && bp.Name == bp.WatchExpr {
+		bp.Name = "watch " + bp.WatchExpr
+	}
+
+	hitCounts := make(map[int64]uint64, len(bp.HitCount))
+	for goroutineID, count := range bp.HitCount {
+		if id, err := strconv.ParseInt(goroutineID, 10, 64); err == nil {
+			hitCounts[id] = count
+		}
+	}
 
 	return Breakpoint{
-		ID:        bp.ID,
-		Name:      bp.Name,
-		Line:      bp.Line,
-		Filename:  bp.File,
-		Disabled:  bp.Disabled,
-		Condition: bp.Cond,
+		ID:         bp.ID,
+		Name:       bp.Name,
+		Line:       bp.Line,
+		Filename:   bp.File,
+		Disabled:   bp.Disabled,
+		Condition:  userCondition(bp.Cond, d.extras[bp.ID].goroutine),
+		WatchExpr:  bp.WatchExpr,
+		WatchType:  WatchType(bp.WatchType),
+		LogMessage: d.extras[bp.ID].logMessage,
+		Temporary:  d.extras[bp.ID].temporary,
+		DependsOn:  d.extras[bp.ID].dependsOn,
+		Group:      d.extras[bp.ID].group,
+
+		GoroutineID: d.extras[bp.ID].goroutine,
+
+		HitCondition:  bp.HitCond,
+		HitCondPerG:   bp.HitCondPerG,
+		TotalHitCount: bp.TotalHitCount,
+		HitCounts:     hitCounts,
+
+		CaptureOnHit: bp.Stacktrace > 0 || bp.LoadArgs != nil || bp.LoadLocals != nil,
 	}
 }
 
+var logExpressionRegex = regexp.MustCompile(`\{([^{}]+)\}`)
+
+func logExpressions(message string) []string {
+	matches := logExpressionRegex.FindAllStringSubmatch(message, -1)
+
+	exprs := make([]string, len(matches))
+	for i, match := range matches {
+		exprs[i] = strings.TrimSpace(match[1])
+	}
+
+	return exprs
+}
+
+// FormatLogMessage replaces every {expr} in message with the value the
+// corresponding expression had when the logpoint was hit.
+func FormatLogMessage(message string, values []Variable) string {
+	i := 0
+	return logExpressionRegex.ReplaceAllStringFunc(message, func(match string) string {
+		if i >= len(values) {
+			return match
+		}
+
+		v := values[i]
+		i++
+		return v.Value
+	})
+}
+
+var packagePathRegex = regexp.MustCompile(`([\w.\-]+(?:/[\w.\-]+)+)\.`)
+
+// addressExpr builds an expression reading a value of type typ at addr.
+func addressExpr(typ string, addr uint64) string {
+	return fmt.Sprintf("*(*%s)(%#x)", quoteTypePackages(typ), addr)
+}
+
+// quoteTypePackages quotes the package paths of typ, paths containing slashes
+// must be quoted for delve to parse the type.
+func quoteTypePackages(typ string) string {
+	return packagePathRegex.ReplaceAllString(typ, `"$1".`)
+}
+
+func apiVarsToInternalVars(vars []api.Variable) []Variable {
+	if len(vars) == 0 {
+		return nil
+	}
+
+	result := make([]Variable, len(vars))
+	for i := range vars {
+		result[i] = apiVarToInternalVar(vars[i])
+	}
+
+	return result
+}
+
 func apiVarToInternalVar(v api.Variable) Variable {
-	return Variable{
-		Name:           v.Name,
+	return apiVarToInternalVarAt(v, v.Name, v.Name)
+}
+
+func apiVarToInternalVarAt(v api.Variable, name string, expr string) Variable {
+	variable := Variable{
+		Name:           name,
+		Type:           v.Type,
+		Kind:           v.Kind,
 		Value:          v.SinglelineString(),
 		MultilineValue: v.MultilineString(" ", "%#v"),
+		Expr:           expr,
+		Children:       apiVarChildren(v, expr, 0),
+		raw:            v.Value,
+	}
+
+	switch v.Kind {
+	case reflect.String:
+		variable.Missing = v.Len - int64(len(v.Value))
+	case reflect.Array, reflect.Slice:
+		variable.Missing = v.Len - int64(len(v.Children))
+	case reflect.Map:
+		variable.Missing = v.Len - int64(len(v.Children)/2)
+	case reflect.Struct:
+		variable.Unloaded = len(v.Children) == 0 && v.Len > 0
+	case reflect.Ptr, reflect.Interface:
+		variable.Unloaded = len(v.Children) == 0 && variable.Value != "nil"
+	}
+	variable.Missing = max(variable.Missing, 0)
+
+	return variable
+}
+
+// LoadMore loads what the load limits left out of v. Slices, arrays and maps
+// get their next page of elements, or all of them when all is set, strings
+// get their full value and variables nested too deep get their children.
+func (d Debugger) LoadMore(v Variable, all bool) (Variable, error) {
+	if v.Expr == "" {
+		return v, fmt.Errorf("error loading %s: the variable can't be reached with an expression", v.Name)
 	}
+
+	state, err := d.client.GetState()
+	if err != nil {
+		return v, fmt.Errorf("error loading %s: debugger state: %w", v.Name, err)
+	}
+	if state.CurrentThread == nil {
+		return v, fmt.Errorf("error loading %s: the program is not stopped", v.Name)
+	}
+	scope := api.EvalScope{GoroutineID: state.CurrentThread.GoroutineID}
+
+	cfg := d.lcfg
+	if all {
+		cfg.MaxArrayValues = maxLoadAll
+	}
+
+	switch {
+	case v.Unloaded:
+	case v.Kind == reflect.String && v.Missing > 0:
+		cfg.MaxStringLen = maxLoadAll
+	case (v.Kind == reflect.Array || v.Kind == reflect.Slice || v.Kind == reflect.Map) && v.Missing > 0:
+		return d.loadNextPage(scope, v, cfg)
+	default:
+		return v, nil
+	}
+
+	loaded, err := d.client.EvalVariable(scope, v.Expr, cfg)
+	if err != nil {
+		return v, fmt.Errorf("error loading %s: %w", v.Name, err)
+	}
+
+	return d.applyPrinters(scope, apiVarToInternalVarAt(*loaded, v.Name, v.Expr)), nil
+}
+
+// loadNextPage loads the elements of v after the ones it already has.
+func (d Debugger) loadNextPage(scope api.EvalScope, v Variable, cfg api.LoadConfig) (Variable, error) {
+	offset := len(v.Children)
+	page, err := d.client.EvalVariable(scope, fmt.Sprintf("(%s)[%d:]", v.Expr, offset), cfg)
+	if err != nil {
+		return v, fmt.Errorf("error loading more of %s: %w", v.Name, err)
+	}
+
+	children := apiVarChildren(*page, v.Expr, offset)
+	for i := range children {
+		children[i] = d.applyPrinters(scope, children[i])
+	}
+
+	loaded := v
+	loaded.Children = append(slices.Clone(v.Children), children...)
+	loaded.Missing = max(v.Missing-int64(len(loaded.Children)-offset), 0)
+
+	return loaded, nil
+}
+
+// apiVarChildren names the children of v the way they are written in Go and
+// builds the expression that reaches each of them from expr.
+func apiVarChildren(v api.Variable, expr string, offset int) []Variable {
+	if len(v.Children) == 0 {
+		return nil
+	}
+
+	var children []Variable
+	switch v.Kind {
+	case reflect.Struct:
+		for _, field := range v.Children {
+			children = append(children, apiVarToInternalVarAt(field, field.Name, expr+"."+field.Name))
+		}
+
+	case reflect.Array, reflect.Slice:
+		for i, elem := range v.Children {
+			i += offset
+			children = append(children, apiVarToInternalVarAt(elem, fmt.Sprintf("[%d]", i), fmt.Sprintf("%s[%d]", expr, i)))
+		}
+
+	case reflect.Map:
+		for i := 0; i+1 < len(v.Children); i += 2 {
+			key, value := v.Children[i], v.Children[i+1]
+
+			var valueExpr string
+			if keyExpr := mapKeyExpr(key); keyExpr != "" {
+				valueExpr = fmt.Sprintf("%s[%s]", expr, keyExpr)
+			}
+
+			children = append(children, apiVarToInternalVarAt(value, "["+key.SinglelineString()+"]", valueExpr))
+		}
+
+	case reflect.Ptr:
+		children = append(children, apiVarToInternalVarAt(v.Children[0], "*", "(*"+expr+")"))
+
+	case reflect.Interface:
+		data := v.Children[0]
+		children = append(children, apiVarToInternalVarAt(data, "data", fmt.Sprintf("%s.(%s)", expr, quoteTypePackages(data.Type))))
+	}
+
+	return children
+}
+
+// mapKeyExpr returns a literal for key that can index its map, keys that
+// can't be written as a literal return an empty string.
+func mapKeyExpr(key api.Variable) string {
+	switch key.Kind {
+	case reflect.String:
+		// a key cut by the load limits would index another entry
+		if key.Len > int64(len(key.Value)) {
+			return ""
+		}
+		return strconv.Quote(key.Value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Bool:
+		return key.Value
+	}
+
+	return ""
 }
@@ -0,0 +1,171 @@
+package debugger
+
+import (
+	"fmt"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-delve/delve/service/api"
+	"github.com/go-delve/delve/service/rpc2"
+)
+
+func TestGoroutineCondition(t *testing.T) {
+	tests := []struct {
+		name        string
+		cond        string
+		goroutineID int64
+		want        string
+	}{
+		{name: "no goroutine", cond: "x > 1", goroutineID: 0, want: "x > 1"},
+		{name: "goroutine only", cond: "", goroutineID: 7, want: "runtime.curg.goid == 7"},
+		{name: "goroutine and condition", cond: "x > 1", goroutineID: 7, want: "runtime.curg.goid == 7 && (x > 1)"},
+		{name: "condition with its own operators", cond: "a || b", goroutineID: 7, want: "runtime.curg.goid == 7 && (a || b)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := goroutineCondition(tt.cond, tt.goroutineID)
+			if got != tt.want {
+				t.Errorf("goroutineCondition(%q, %d) = %q, want %q", tt.cond, tt.goroutineID, got, tt.want)
+			}
+
+			if back := userCondition(got, tt.goroutineID); back != tt.cond {
+				t.Errorf("userCondition(%q, %d) = %q, want %q", got, tt.goroutineID, back, tt.cond)
+			}
+		})
+	}
+}
+
+func TestUserCondition(t *testing.T) {
+	tests := []struct {
+		name        string
+		cond        string
+		goroutineID int64
+		want        string
+	}{
+		{name: "no goroutine", cond: "x > 1", goroutineID: 0, want: "x > 1"},
+		{name: "goroutine only", cond: "runtime.curg.goid == 7", goroutineID: 7, want: ""},
+		{name: "goroutine and condition", cond: "runtime.curg.goid == 7 && (x > 1)", goroutineID: 7, want: "x > 1"},
+		{name: "nested parentheses", cond: "runtime.curg.goid == 7 && ((a || b) && c)", goroutineID: 7, want: "(a || b) && c"},
+		{name: "check of another goroutine", cond: "runtime.curg.goid == 8 && (x > 1)", goroutineID: 7, want: "runtime.curg.goid == 8 && (x > 1)"},
+		{name: "goroutine ID sharing a prefix", cond: "runtime.curg.goid == 12", goroutineID: 1, want: "runtime.curg.goid == 12"},
+		{name: "condition without the check", cond: "x > 1", goroutineID: 7, want: "x > 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := userCondition(tt.cond, tt.goroutineID); got != tt.want {
+				t.Errorf("userCondition(%q, %d) = %q, want %q", tt.cond, tt.goroutineID, got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeServer answers FindLocation like Delve does for a file where only the
+// lines in code have instructions.
+type fakeServer struct {
+	code map[int]bool
+}
+
+func (s *fakeServer) SetApiVersion(in api.SetAPIVersionIn, out *api.SetAPIVersionOut) error {
+	return nil
+}
+
+func (s *fakeServer) FindLocation(in rpc2.FindLocationIn, out *rpc2.FindLocationOut) error {
+	i := strings.LastIndex(in.Loc, ":")
+	file := in.Loc[:i]
+	line, err := strconv.Atoi(in.Loc[i+1:])
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case s.code[line]:
+		pc := uint64(0x1000 + line)
+		out.Locations = []api.Location{{File: file, Line: line, PC: pc, PCs: []uint64{pc}}}
+	case in.IncludeNonExecutableLines:
+		out.Locations = []api.Location{{File: file, Line: line}}
+	default:
+		return fmt.Errorf("could not find statement at %s:%d, please use a line with a statement", file, line)
+	}
+
+	return nil
+}
+
+func newFakeDebugger(t *testing.T, s *fakeServer) Debugger {
+	t.Helper()
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("RPCServer", s); err != nil {
+		t.Fatal(err)
+	}
+
+	serverConn, clientConn := net.Pipe()
+	go server.ServeCodec(jsonrpc.NewServerCodec(serverConn))
+
+	client := rpc2.NewClientFromConn(clientConn)
+	t.Cleanup(func() { clientConn.Close() })
+
+	return Debugger{client: client}
+}
+
+func TestResolveLine(t *testing.T) {
+	source := `package main
+
+func main() {
+	// a comment
+
+	x := 1
+	// a comment before the end
+}
+
+func other() {
+	// one
+	// two
+	// three
+	// four
+	// five
+	y := 2
+	_ = y
+}
+`
+	filename := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(filename, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	d := newFakeDebugger(t, &fakeServer{code: map[int]bool{3: true, 6: true, 10: true, 16: true, 17: true}})
+
+	check := func(line int, want int) {
+		t.Helper()
+		got, err := d.ResolveLine(filename, line)
+		if err != nil {
+			t.Fatalf("ResolveLine(%d) error = %v", line, err)
+		}
+		if got != want {
+			t.Errorf("ResolveLine(%d) = %d, want %d", line, got, want)
+		}
+	}
+
+	check(6, 6)
+	// comment and blank lines move to the next line with code
+	check(4, 6)
+	check(5, 6)
+
+	// the next function is not the code of the line
+	if got, err := d.ResolveLine(filename, 7); err == nil {
+		t.Errorf("ResolveLine(7) = %d, want no code found before the end of main", got)
+	}
+
+	// nor code found too far away
+	if got, err := d.ResolveLine(filename, 11); err == nil {
+		t.Errorf("ResolveLine(11) = %d, want no code found in the next lines", got)
+	}
+	check(12, 16)
+}
@@ -0,0 +1,433 @@
+package debugger
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is how a value is displayed. Formats are named after the fmt verbs
+// they resemble, FormatDefault keeps Delve's representation.
+type Format uint8
+
+const (
+	FormatDefault Format = iota
+	// FormatHex shows integers in base 16 and byte slices as a hex dump.
+	FormatHex
+	FormatOctal
+	FormatBinary
+	// FormatChar shows integers as the character they encode.
+	FormatChar
+	// FormatString shows byte slices as UTF-8 text and time.Duration and
+	// time.Time values in a human readable way.
+	FormatString
+	FormatJSON
+)
+
+var formatVerbs = [...]string{
+	FormatDefault: "v",
+	FormatHex:     "x",
+	FormatOctal:   "o",
+	FormatBinary:  "b",
+	FormatChar:    "c",
+	FormatString:  "s",
+	FormatJSON:    "j",
+}
+
+func (f Format) String() string {
+	return formatVerbs[f]
+}
+
+// ParseFormat returns the format of a verb like x or %x. d is accepted for
+// decimal, which is how Delve shows integers already.
+func ParseFormat(verb string) (Format, bool) {
+	verb = strings.TrimPrefix(verb, "%")
+	if verb == "d" {
+		return FormatDefault, true
+	}
+
+	for f, v := range formatVerbs {
+		if v == verb {
+			return Format(f), true
+		}
+	}
+
+	return FormatDefault, false
+}
+
+// SplitFormat splits a leading verb off expr, as in "%x n". Expressions
+// without one get FormatDefault.
+func SplitFormat(expr string) (Format, string) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "%") {
+		return FormatDefault, expr
+	}
+
+	verb, rest, found := strings.Cut(expr, " ")
+	f, ok := ParseFormat(verb)
+	if !found || !ok {
+		return FormatDefault, expr
+	}
+
+	return f, strings.TrimSpace(rest)
+}
+
+// WithFormat prefixes expr with the verb of f, the reverse of SplitFormat.
+func WithFormat(f Format, expr string) string {
+	if f == FormatDefault {
+		return expr
+	}
+
+	return "%" + f.String() + " " + expr
+}
+
+// Formats returns the formats that apply to v, FormatDefault first.
+func Formats(v Variable) []Format {
+	formats := []Format{FormatDefault}
+
+	switch {
+	case isInteger(v.Kind):
+		formats = append(formats, FormatHex, FormatOctal, FormatBinary, FormatChar)
+		if v.Type == "time.Duration" {
+			formats = append(formats, FormatString)
+		}
+
+	case isBytes(v):
+		formats = append(formats, FormatString, FormatHex)
+
+	case v.Type == "time.Time":
+		formats = append(formats, FormatString, FormatJSON)
+
+	case v.Kind == reflect.Struct, v.Kind == reflect.Map, v.Kind == reflect.Slice,
+		v.Kind == reflect.Array, v.Kind == reflect.Ptr, v.Kind == reflect.Interface:
+		formats = append(formats, FormatJSON)
+	}
+
+	return formats
+}
+
+// NextFormat returns the format after f among the ones that apply to v,
+// going back to FormatDefault after the last one.
+func NextFormat(v Variable, f Format) Format {
+	formats := Formats(v)
+	for i := range formats {
+		if formats[i] == f {
+			return formats[(i+1)%len(formats)]
+		}
+	}
+
+	return FormatDefault
+}
+
+// Format returns v with its value rendered in format f. Formats that don't
+// apply to v leave it unchanged.
+func (v Variable) Format(f Format) Variable {
+	var value, multiline string
+	var ok bool
+
+	switch f {
+	case FormatHex, FormatOctal, FormatBinary, FormatChar:
+		if isBytes(v) && f == FormatHex {
+			b := bytesOf(v)
+			value, multiline, ok = hexBytes(b)+v.missingSuffix(), strings.TrimSuffix(hex.Dump(b), "\n")+v.missingSuffix(), true
+			break
+		}
+		value, ok = formatInteger(v, f)
+		multiline = value
+
+	case FormatString:
+		value, ok = formatText(v)
+		multiline = value
+
+	case FormatJSON:
+		value, ok = jsonValue(v), true
+
+		indented, err := indentJSON(value)
+		if err != nil {
+			indented = value
+		}
+		multiline = indented
+	}
+
+	if !ok {
+		return v
+	}
+
+	v.Value, v.MultilineValue = value, multiline
+	return v
+}
+
+func (v Variable) missingSuffix() string {
+	if v.Missing > 0 {
+		return fmt.Sprintf(" ...+%d more", v.Missing)
+	}
+
+	return ""
+}
+
+func formatInteger(v Variable, f Format) (string, bool) {
+	if !isInteger(v.Kind) {
+		return "", false
+	}
+
+	var n any
+	if isUnsigned(v.Kind) {
+		u, err := strconv.ParseUint(v.raw, 10, 64)
+		if err != nil {
+			return "", false
+		}
+		n = u
+	} else {
+		i, err := strconv.ParseInt(v.raw, 10, 64)
+		if err != nil {
+			return "", false
+		}
+		n = i
+	}
+
+	switch f {
+	case FormatHex:
+		return fmt.Sprintf("%#x", n), true
+	case FormatOctal:
+		return fmt.Sprintf("%O", n), true
+	case FormatBinary:
+		return fmt.Sprintf("%#b", n), true
+	default:
+		return fmt.Sprintf("%q", n), true
+	}
+}
+
+func formatText(v Variable) (string, bool) {
+	switch {
+	case isBytes(v):
+		return strconv.Quote(string(bytesOf(v))) + v.missingSuffix(), true
+
+	case v.Type == "time.Duration":
+		n, err := strconv.ParseInt(v.raw, 10, 64)
+		if err != nil {
+			return "", false
+		}
+		return time.Duration(n).String(), true
+
+	case v.Type == "time.Time":
+		t, ok := timeOf(v)
+		if !ok {
+			return "", false
+		}
+		return t.Format(time.RFC3339Nano), true
+	}
+
+	return "", false
+}
+
+// the encoding of time.Time, see the time package
+const (
+	hasMonotonic   = 1 << 63
+	nsecMask       = 1<<30 - 1
+	nsecShift      = 30
+	secondsPerDay  = 86400
+	unixToInternal = (1969*365 + 1969/4 - 1969/100 + 1969/400) * secondsPerDay
+	wallToInternal = (1884*365 + 1884/4 - 1884/100 + 1884/400) * secondsPerDay
+)
+
+// timeOf decodes a time.Time from its wall, ext and loc fields. The location
+// is looked up by name on this machine, the same way Delve prints times.
+func timeOf(v Variable) (time.Time, bool) {
+	var wall uint64
+	var ext int64
+	var loc *time.Location
+	var found int
+
+	for _, field := range v.Children {
+		var err error
+		switch field.Name {
+		case "wall":
+			wall, err = strconv.ParseUint(field.raw, 10, 64)
+			found++
+		case "ext":
+			ext, err = strconv.ParseInt(field.raw, 10, 64)
+			found++
+		case "loc":
+			loc, err = locationOf(field)
+			found++
+		}
+		if err != nil {
+			return time.Time{}, false
+		}
+	}
+	if found != 3 {
+		return time.Time{}, false
+	}
+
+	nsec := int64(wall & nsecMask)
+	sec := ext
+	if wall&hasMonotonic != 0 {
+		sec = wallToInternal + int64(wall<<1>>(nsecShift+1))
+	}
+
+	return time.Unix(sec-unixToInternal, nsec).In(loc), true
+}
+
+// locationOf loads the time.Location the loc pointer of a time.Time points
+// to, nil is UTC.
+func locationOf(loc Variable) (*time.Location, error) {
+	if loc.Value == "nil" {
+		return time.UTC, nil
+	}
+	if len(loc.Children) == 0 {
+		return nil, errors.New("location not loaded")
+	}
+
+	for _, field := range loc.Children[0].Children {
+		if field.Name == "name" {
+			return time.LoadLocation(field.raw)
+		}
+	}
+
+	return nil, errors.New("location not loaded")
+}
+
+// jsonValue renders v as compact JSON. Fields keep their declaration order
+// and values that have no JSON counterpart are rendered as strings.
+func jsonValue(v Variable) string {
+	switch v.Kind {
+	case reflect.Struct:
+		if v.Type == "time.Time" {
+			if t, ok := timeOf(v); ok {
+				return jsonString(t.Format(time.RFC3339Nano))
+			}
+		}
+		if v.Unloaded {
+			return jsonString(v.Value)
+		}
+
+		fields := make([]string, len(v.Children))
+		for i, field := range v.Children {
+			fields[i] = jsonString(field.Name) + ":" + jsonValue(field)
+		}
+		return "{" + strings.Join(fields, ",") + "}"
+
+	case reflect.Map:
+		if v.Value == "nil" {
+			return "null"
+		}
+
+		entries := make([]string, len(v.Children))
+		for i, value := range v.Children {
+			key := strings.TrimSuffix(strings.TrimPrefix(value.Name, "["), "]")
+			if unquoted, err := strconv.Unquote(key); err == nil {
+				key = unquoted
+			}
+			entries[i] = jsonString(key) + ":" + jsonValue(value)
+		}
+		if v.Missing > 0 {
+			entries = append(entries, jsonString("...")+":"+jsonString(fmt.Sprintf("+%d more", v.Missing)))
+		}
+		return "{" + strings.Join(entries, ",") + "}"
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind == reflect.Slice && v.Value == "nil" {
+			return "null"
+		}
+
+		elems := make([]string, len(v.Children))
+		for i, elem := range v.Children {
+			elems[i] = jsonValue(elem)
+		}
+		if v.Missing > 0 {
+			elems = append(elems, jsonString(fmt.Sprintf("...+%d more", v.Missing)))
+		}
+		return "[" + strings.Join(elems, ",") + "]"
+
+	case reflect.Ptr, reflect.Interface:
+		if len(v.Children) == 1 {
+			return jsonValue(v.Children[0])
+		}
+		if v.Value == "nil" {
+			return "null"
+		}
+		return jsonString(v.Value)
+
+	case reflect.String:
+		return jsonString(v.raw + v.missingSuffix())
+
+	case reflect.Bool, reflect.Float32, reflect.Float64:
+		if json.Valid([]byte(v.raw)) {
+			return v.raw
+		}
+		return jsonString(v.raw)
+	}
+
+	if isInteger(v.Kind) && v.raw != "" {
+		return v.raw
+	}
+
+	return jsonString(v.Value)
+}
+
+func jsonString(s string) string {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return strconv.Quote(s)
+	}
+
+	return string(b)
+}
+
+func indentJSON(s string) (string, error) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(s), "", "  "); err != nil {
+		return "", fmt.Errorf("error indenting json: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+func hexBytes(b []byte) string {
+	parts := make([]string, len(b))
+	for i := range b {
+		parts[i] = fmt.Sprintf("%02x", b[i])
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func bytesOf(v Variable) []byte {
+	b := make([]byte, 0, len(v.Children))
+	for _, elem := range v.Children {
+		n, err := strconv.ParseUint(elem.raw, 10, 8)
+		if err != nil {
+			break
+		}
+		b = append(b, byte(n))
+	}
+
+	return b
+}
+
+func isBytes(v Variable) bool {
+	if v.Kind != reflect.Slice && v.Kind != reflect.Array {
+		return false
+	}
+
+	if strings.HasSuffix(v.Type, "]uint8") || strings.HasSuffix(v.Type, "]byte") {
+		return true
+	}
+
+	// named types, like uuid.UUID, are told by their elements
+	return len(v.Children) > 0 && (v.Children[0].Type == "uint8" || v.Children[0].Type == "byte")
+}
+
+func isInteger(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Uintptr
+}
+
+func isUnsigned(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
@@ -0,0 +1,335 @@
+package debugger
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		verb   string
+		want   Format
+		wantOK bool
+	}{
+		{verb: "v", want: FormatDefault, wantOK: true},
+		{verb: "d", want: FormatDefault, wantOK: true},
+		{verb: "x", want: FormatHex, wantOK: true},
+		{verb: "%x", want: FormatHex, wantOK: true},
+		{verb: "o", want: FormatOctal, wantOK: true},
+		{verb: "b", want: FormatBinary, wantOK: true},
+		{verb: "c", want: FormatChar, wantOK: true},
+		{verb: "s", want: FormatString, wantOK: true},
+		{verb: "j", want: FormatJSON, wantOK: true},
+		{verb: "q", want: FormatDefault, wantOK: false},
+		{verb: "", want: FormatDefault, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.verb, func(t *testing.T) {
+			got, ok := ParseFormat(tt.verb)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ParseFormat(%q) = %v, %t, want %v, %t", tt.verb, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSplitFormat(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantFormat Format
+		wantExpr   string
+	}{
+		{input: "n", wantFormat: FormatDefault, wantExpr: "n"},
+		{input: "%x n", wantFormat: FormatHex, wantExpr: "n"},
+		{input: "  %j  s.field  ", wantFormat: FormatJSON, wantExpr: "s.field"},
+		{input: "%q n", wantFormat: FormatDefault, wantExpr: "%q n"},
+		{input: "%x", wantFormat: FormatDefault, wantExpr: "%x"},
+		{input: "n % 2", wantFormat: FormatDefault, wantExpr: "n % 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			format, expr := SplitFormat(tt.input)
+			if format != tt.wantFormat || expr != tt.wantExpr {
+				t.Errorf("SplitFormat(%q) = %v, %q, want %v, %q", tt.input, format, expr, tt.wantFormat, tt.wantExpr)
+			}
+
+			if format != FormatDefault {
+				if got := WithFormat(format, expr); got != "%"+format.String()+" "+expr {
+					t.Errorf("WithFormat(%v, %q) = %q", format, expr, got)
+				}
+			}
+		})
+	}
+}
+
+func TestNextFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		v    Variable
+		f    Format
+		want Format
+	}{
+		{name: "integer", v: intVar(10), f: FormatDefault, want: FormatHex},
+		{name: "integer wraps around", v: intVar(10), f: FormatChar, want: FormatDefault},
+		{name: "duration", v: Variable{Type: "time.Duration", Kind: reflect.Int64, raw: "1"}, f: FormatChar, want: FormatString},
+		{name: "bytes", v: bytesVar("ab"), f: FormatDefault, want: FormatString},
+		{name: "struct", v: Variable{Kind: reflect.Struct}, f: FormatDefault, want: FormatJSON},
+		{name: "bool has no other format", v: Variable{Kind: reflect.Bool}, f: FormatDefault, want: FormatDefault},
+		{name: "format not applying", v: Variable{Kind: reflect.Struct}, f: FormatHex, want: FormatDefault},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextFormat(tt.v, tt.f); got != tt.want {
+				t.Errorf("NextFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatInteger(t *testing.T) {
+	tests := []struct {
+		name   string
+		v      Variable
+		f      Format
+		want   string
+		wantOK bool
+	}{
+		{name: "hex", v: intVar(255), f: FormatHex, want: "0xff", wantOK: true},
+		{name: "octal", v: intVar(8), f: FormatOctal, want: "0o10", wantOK: true},
+		{name: "binary", v: intVar(5), f: FormatBinary, want: "0b101", wantOK: true},
+		{name: "char", v: intVar(65), f: FormatChar, want: "'A'", wantOK: true},
+		{name: "negative", v: intVar(-1), f: FormatHex, want: "-0x1", wantOK: true},
+		{name: "unsigned max", v: Variable{Kind: reflect.Uint64, raw: "18446744073709551615"}, f: FormatHex, want: "0xffffffffffffffff", wantOK: true},
+		{name: "not an integer", v: Variable{Kind: reflect.String, raw: "10"}, f: FormatHex, wantOK: false},
+		{name: "unreadable", v: Variable{Kind: reflect.Int, raw: ""}, f: FormatHex, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := formatInteger(tt.v, tt.f)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("formatInteger() = %q, %t, want %q, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestFormatText(t *testing.T) {
+	partial := bytesVar("hi")
+	partial.Missing = 3
+
+	tests := []struct {
+		name   string
+		v      Variable
+		want   string
+		wantOK bool
+	}{
+		{name: "bytes", v: bytesVar("hi\n"), want: `"hi\n"`, wantOK: true},
+		{name: "partially loaded bytes", v: partial, want: `"hi" ...+3 more`, wantOK: true},
+		{name: "duration", v: Variable{Type: "time.Duration", Kind: reflect.Int64, raw: "1500000000"}, want: "1.5s", wantOK: true},
+		{name: "time", v: timeVar(time.Date(2024, 3, 1, 12, 0, 0, 5, time.UTC), false), want: "2024-03-01T12:00:00.000000005Z", wantOK: true},
+		{name: "string has no text format", v: Variable{Kind: reflect.String, raw: "s"}, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := formatText(tt.v)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("formatText() = %q, %t, want %q, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestTimeOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		v      Variable
+		want   time.Time
+		wantOK bool
+	}{
+		{
+			name:   "wall clock only",
+			v:      timeVar(time.Date(2024, 3, 1, 12, 30, 0, 42, time.UTC), false),
+			want:   time.Date(2024, 3, 1, 12, 30, 0, 42, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "with monotonic reading",
+			v:      timeVar(time.Date(2024, 3, 1, 12, 30, 0, 42, time.UTC), true),
+			want:   time.Date(2024, 3, 1, 12, 30, 0, 42, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "zero time",
+			v:      timeVar(time.Time{}, false),
+			want:   time.Time{},
+			wantOK: true,
+		},
+		{
+			name:   "missing field",
+			v:      Variable{Type: "time.Time", Kind: reflect.Struct, Children: []Variable{{Name: "wall", raw: "0"}}},
+			wantOK: false,
+		},
+		{
+			name:   "unreadable field",
+			v:      Variable{Type: "time.Time", Kind: reflect.Struct, Children: []Variable{{Name: "wall", raw: "?"}, {Name: "ext", raw: "0"}}},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := timeOf(tt.v)
+			if !got.Equal(tt.want) || ok != tt.wantOK {
+				t.Errorf("timeOf() = %v, %t, want %v, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestTimeOfLocation(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := time.Date(2024, 3, 1, 12, 30, 0, 0, newYork)
+	got, ok := timeOf(timeVar(want, true))
+	if !ok || !got.Equal(want) || got.Location().String() != "America/New_York" {
+		t.Errorf("timeOf() = %v, %t, want %v, true", got, ok, want)
+	}
+	if text, _ := formatText(timeVar(want, false)); text != "2024-03-01T12:30:00-05:00" {
+		t.Errorf("formatText() = %q, want the time in New York", text)
+	}
+
+	unknown := timeVar(want, false)
+	unknown.Children[2].Children[0].Children[0].raw = "Nowhere/Unknown"
+	if got, ok := timeOf(unknown); ok {
+		t.Errorf("timeOf() = %v, want no time for an unknown location", got)
+	}
+
+	unloaded := timeVar(want, false)
+	unloaded.Children[2].Children = nil
+	if got, ok := timeOf(unloaded); ok {
+		t.Errorf("timeOf() = %v, want no time for a location that wasn't loaded", got)
+	}
+}
+
+func TestJSONValue(t *testing.T) {
+	partialSlice := Variable{Kind: reflect.Slice, Type: "[]int", Children: []Variable{intVar(1), intVar(2)}, Missing: 8}
+	partialMap := Variable{Kind: reflect.Map, Type: "map[string]int", Children: []Variable{
+		{Name: `["a"]`, Kind: reflect.Int, raw: "1"},
+	}, Missing: 2}
+
+	tests := []struct {
+		name string
+		v    Variable
+		want string
+	}{
+		{
+			name: "struct keeps field order",
+			v: Variable{Kind: reflect.Struct, Children: []Variable{
+				{Name: "Z", Kind: reflect.String, raw: `a "quoted" value`},
+				{Name: "A", Kind: reflect.Bool, raw: "true"},
+				{Name: "F", Kind: reflect.Float64, raw: "+Inf"},
+			}},
+			want: `{"Z":"a \"quoted\" value","A":true,"F":"+Inf"}`,
+		},
+		{
+			name: "map keys unquoted",
+			v: Variable{Kind: reflect.Map, Children: []Variable{
+				{Name: `["a"]`, Kind: reflect.Int, raw: "1"},
+				{Name: "[2]", Kind: reflect.Int, raw: "2"},
+			}},
+			want: `{"a":1,"2":2}`,
+		},
+		{name: "nil map", v: Variable{Kind: reflect.Map, Value: "nil"}, want: "null"},
+		{name: "nil slice", v: Variable{Kind: reflect.Slice, Value: "nil"}, want: "null"},
+		{name: "nil pointer", v: Variable{Kind: reflect.Ptr, Value: "nil"}, want: "null"},
+		{name: "pointer followed", v: Variable{Kind: reflect.Ptr, Children: []Variable{intVar(3)}}, want: "3"},
+		{name: "partial slice", v: partialSlice, want: `[1,2,"...+8 more"]`},
+		{name: "partial map", v: partialMap, want: `{"a":1,"...":"+2 more"}`},
+		{name: "partial string", v: Variable{Kind: reflect.String, raw: "abc", Missing: 5}, want: `"abc ...+5 more"`},
+		{name: "time", v: timeVar(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), false), want: `"2024-03-01T00:00:00Z"`},
+		{name: "unloaded struct", v: Variable{Kind: reflect.Struct, Value: "(unloaded)", Unloaded: true}, want: `"(unloaded)"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jsonValue(tt.v); got != tt.want {
+				t.Errorf("jsonValue() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVariableFormat(t *testing.T) {
+	tests := []struct {
+		name          string
+		v             Variable
+		f             Format
+		wantValue     string
+		wantMultiline string
+	}{
+		{name: "bytes in hex", v: bytesVar("ab"), f: FormatHex, wantValue: "61 62", wantMultiline: "00000000  61 62                                             |ab|"},
+		{name: "named bytes in hex", v: Variable{Type: "uuid.UUID", Kind: reflect.Array, Children: bytesVar("\x01").Children}, f: FormatHex, wantValue: "01", wantMultiline: "00000000  01                                                |.|"},
+		{name: "json indented", v: Variable{Kind: reflect.Slice, Children: []Variable{intVar(1)}}, f: FormatJSON, wantValue: "[1]", wantMultiline: "[\n  1\n]"},
+		{name: "format not applying", v: Variable{Kind: reflect.Bool, Value: "true", MultilineValue: "true"}, f: FormatHex, wantValue: "true", wantMultiline: "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.v.Format(tt.f)
+			if got.Value != tt.wantValue || got.MultilineValue != tt.wantMultiline {
+				t.Errorf("Format() = %q, %q, want %q, %q", got.Value, got.MultilineValue, tt.wantValue, tt.wantMultiline)
+			}
+		})
+	}
+}
+
+func intVar(n int64) Variable {
+	return Variable{Type: "int", Kind: reflect.Int, raw: strconv.FormatInt(n, 10)}
+}
+
+func bytesVar(s string) Variable {
+	v := Variable{Type: "[]uint8", Kind: reflect.Slice}
+	for i := range len(s) {
+		n := strconv.Itoa(int(s[i]))
+		v.Children = append(v.Children, Variable{Type: "uint8", Kind: reflect.Uint8, Value: n, raw: n})
+	}
+
+	return v
+}
+
+// timeVar encodes t in the wall and ext fields the way the time package does.
+func timeVar(t time.Time, monotonic bool) Variable {
+	sec := t.Unix() + unixToInternal
+	wall, ext := uint64(t.Nanosecond()), sec
+	if monotonic {
+		wall |= hasMonotonic | uint64(sec-wallToInternal)<<nsecShift
+		ext = 12345
+	}
+
+	// the time package stores UTC as a nil location
+	loc := Variable{Name: "loc", Type: "*time.Location", Kind: reflect.Ptr, Value: "nil"}
+	if t.Location() != time.UTC {
+		name := t.Location().String()
+		loc.Value = ""
+		loc.Children = []Variable{{Name: "*", Type: "time.Location", Kind: reflect.Struct, Children: []Variable{
+			{Name: "name", Type: "string", Kind: reflect.String, Value: strconv.Quote(name), raw: name},
+		}}}
+	}
+
+	return Variable{Type: "time.Time", Kind: reflect.Struct, Children: []Variable{
+		{Name: "wall", Kind: reflect.Uint64, raw: strconv.FormatUint(wall, 10)},
+		{Name: "ext", Kind: reflect.Int64, raw: strconv.FormatInt(ext, 10)},
+		loc,
+	}}
+}
@@ -0,0 +1,204 @@
+package debugger
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/andersonjoseph/drill/internal/config"
+	"github.com/go-delve/delve/service/api"
+)
+
+var (
+	templateFieldRegex = regexp.MustCompile(`\{((?:[^{}:\[]|\[[^\]]*\])*)(?::(\w))?\}`)
+	templatePathRegex  = regexp.MustCompile(`([^.\[\]]+)|\[(\d+)(?::(\d+))?\]`)
+	typePackageRegex   = regexp.MustCompile(`^[\w.\-~]+(/[\w.\-~]+)*$`)
+)
+
+// SetPrinters replaces the display rules applied to the loaded values.
+func (d *Debugger) SetPrinters(printers []config.Printer) {
+	d.printers = printers
+}
+
+// applyPrinters renders v and its children with the printers matching their
+// types. Values a printer fails on keep Delve's representation.
+func (d Debugger) applyPrinters(scope api.EvalScope, v Variable) Variable {
+	if len(d.printers) == 0 {
+		return v
+	}
+
+	return d.applyPrintersAt(scope, v, true)
+}
+
+// applyPrintersAt only runs expr printers on the top level value, each of
+// them costs a round trip to Delve. Nested values keep Delve's representation
+// unless their printer is a template, expanding them doesn't evaluate it
+// either: only the values LoadMore fetches are printed as top level ones.
+func (d Debugger) applyPrintersAt(scope api.EvalScope, v Variable, topLevel bool) Variable {
+	if len(v.Children) > 0 {
+		children := make([]Variable, len(v.Children))
+		for i := range v.Children {
+			children[i] = d.applyPrintersAt(scope, v.Children[i], false)
+		}
+		v.Children = children
+	}
+
+	printer, ok := d.printerFor(v.Type)
+	if !ok {
+		return v
+	}
+
+	var value string
+	var err error
+	switch {
+	case printer.Template != "":
+		value, err = renderTemplate(printer.Template, v)
+	case topLevel:
+		value, err = d.evalPrinter(scope, printer.Expr, v)
+	default:
+		return v
+	}
+	if err != nil {
+		return v
+	}
+
+	v.Value, v.MultilineValue = value, value
+	return v
+}
+
+// printerFor returns the last printer matching typ, so the rules of the
+// project config win over the user ones.
+func (d Debugger) printerFor(typ string) (config.Printer, bool) {
+	for i := len(d.printers) - 1; i >= 0; i-- {
+		if matchesType(typ, d.printers[i].Type) {
+			return d.printers[i], true
+		}
+	}
+
+	return config.Printer{}, false
+}
+
+// matchesType reports whether typ is the named type t, given either in full
+// or as package.Name. Composite types containing t, like []t, don't match.
+func matchesType(typ string, t string) bool {
+	if typ == t {
+		return true
+	}
+
+	pkg, found := strings.CutSuffix(typ, "/"+t)
+	return found && typePackageRegex.MatchString(pkg)
+}
+
+func (d Debugger) evalPrinter(scope api.EvalScope, expr string, v Variable) (string, error) {
+	if v.Expr == "" {
+		return "", fmt.Errorf("error printing %s: the variable can't be reached with an expression", v.Name)
+	}
+
+	result, err := d.client.EvalVariable(scope, strings.ReplaceAll(expr, "$", "("+v.Expr+")"), d.lcfg)
+	if err != nil {
+		return "", fmt.Errorf("error printing %s: %w", v.Name, err)
+	}
+	if result.Unreadable != "" {
+		return "", fmt.Errorf("error printing %s: %s", v.Name, result.Unreadable)
+	}
+
+	if result.Kind == reflect.String {
+		return result.Value, nil
+	}
+
+	return result.SinglelineString(), nil
+}
+
+// renderTemplate replaces each {path} of tmpl with the value at path, a chain
+// of field names, [i] indexes and [i:j] ranges, empty for the value itself.
+// {path:verb} renders it in the format of verb, bytes in hex are written
+// without separators.
+func renderTemplate(tmpl string, v Variable) (string, error) {
+	var err error
+
+	rendered := templateFieldRegex.ReplaceAllStringFunc(tmpl, func(match string) string {
+		parts := templateFieldRegex.FindStringSubmatch(match)
+
+		field, fieldErr := resolvePath(v, parts[1])
+		if fieldErr != nil {
+			err = fieldErr
+			return match
+		}
+
+		if parts[2] == "" {
+			return field.Value
+		}
+
+		format, ok := ParseFormat(parts[2])
+		if !ok {
+			err = fmt.Errorf("error printing %s: unknown verb %q", v.Name, parts[2])
+			return match
+		}
+
+		if format == FormatHex && isBytes(field) {
+			return hex.EncodeToString(bytesOf(field))
+		}
+		return field.Format(format).Value
+	})
+
+	return rendered, err
+}
+
+func resolvePath(v Variable, path string) (Variable, error) {
+	root := v.Name
+	for _, step := range templatePathRegex.FindAllStringSubmatch(strings.TrimSpace(path), -1) {
+		// pointers and interfaces are followed to what they hold
+		for (v.Kind == reflect.Ptr || v.Kind == reflect.Interface) && len(v.Children) == 1 {
+			v = v.Children[0]
+		}
+
+		name, from, to := step[1], step[2], step[3]
+		switch {
+		case name != "":
+			field, ok := childNamed(v, name)
+			if !ok {
+				return v, fmt.Errorf("error printing %s: no field %s", root, name)
+			}
+			v = field
+
+		case to == "":
+			i, _ := strconv.Atoi(from)
+			if i >= len(v.Children) {
+				return v, fmt.Errorf("error printing %s: index %d not loaded", root, i)
+			}
+			v = v.Children[i]
+
+		default:
+			i, _ := strconv.Atoi(from)
+			j, _ := strconv.Atoi(to)
+			if i > j || j > len(v.Children) {
+				return v, fmt.Errorf("error printing %s: range [%d:%d] not loaded", root, i, j)
+			}
+			children := v.Children[i:j]
+			values := make([]string, len(children))
+			for k := range children {
+				values[k] = children[k].Value
+			}
+			v = Variable{Name: v.Name, Type: v.Type, Kind: reflect.Slice, Value: "[" + strings.Join(values, " ") + "]", Children: children}
+		}
+	}
+
+	if v.Unloaded {
+		return v, fmt.Errorf("error printing %s: value not loaded", root)
+	}
+
+	return v, nil
+}
+
+func childNamed(v Variable, name string) (Variable, bool) {
+	for _, child := range v.Children {
+		if child.Name == name {
+			return child, true
+		}
+	}
+
+	return Variable{}, false
+}
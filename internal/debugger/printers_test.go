@@ -0,0 +1,147 @@
+package debugger
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/andersonjoseph/drill/internal/config"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	point := Variable{Name: "p", Type: "main.Point", Kind: reflect.Struct, Children: []Variable{
+		{Name: "X", Kind: reflect.Int, Value: "1", raw: "1"},
+		{Name: "Y", Kind: reflect.Int, Value: "255", raw: "255"},
+	}}
+
+	id := bytesVar("\xde\xad\xbe\xef")
+	id.Name, id.Type, id.Value = "id", "main.ID", "[222 173 190 239]"
+
+	tests := []struct {
+		name    string
+		tmpl    string
+		v       Variable
+		want    string
+		wantErr bool
+	}{
+		{name: "fields", tmpl: "({X}, {Y})", v: point, want: "(1, 255)"},
+		{name: "verb", tmpl: "{Y:x}", v: point, want: "0xff"},
+		{name: "value itself", tmpl: "<{}>", v: Variable{Name: "n", Kind: reflect.Int, Value: "7", raw: "7"}, want: "<7>"},
+		{name: "bytes in hex without separators", tmpl: "{:x}", v: id, want: "deadbeef"},
+		{name: "range", tmpl: "{[0:2]:x}", v: id, want: "dead"},
+		{name: "index", tmpl: "{[3]}", v: id, want: "239"},
+		{name: "text around braces kept", tmpl: "id {", v: id, want: "id {"},
+		{name: "unknown field", tmpl: "{Z}", v: point, wantErr: true},
+		{name: "unknown verb", tmpl: "{X:q}", v: point, wantErr: true},
+		{name: "index not loaded", tmpl: "{[9]}", v: id, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderTemplate(tt.tmpl, tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderTemplate() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("renderTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	inner := Variable{Name: "Inner", Kind: reflect.Struct, Children: []Variable{
+		{Name: "N", Kind: reflect.Int, Value: "3"},
+	}}
+	v := Variable{Name: "v", Kind: reflect.Ptr, Children: []Variable{
+		{Name: "", Kind: reflect.Struct, Children: []Variable{
+			{Name: "Ptr", Kind: reflect.Ptr, Children: []Variable{inner}},
+			{Name: "Items", Kind: reflect.Slice, Children: []Variable{
+				{Name: "[0]", Value: "a"},
+				{Name: "[1]", Value: "b"},
+				{Name: "[2]", Value: "c"},
+			}},
+			{Name: "Deep", Kind: reflect.Struct, Value: "(unloaded)", Unloaded: true},
+		}},
+	}}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{name: "pointers followed", path: "Ptr.N", want: "3"},
+		{name: "index", path: "Items[1]", want: "b"},
+		{name: "range", path: "Items[1:3]", want: "[b c]"},
+		{name: "spaces trimmed", path: " Items[0] ", want: "a"},
+		{name: "missing field", path: "Nope", wantErr: true},
+		{name: "index out of range", path: "Items[3]", wantErr: true},
+		{name: "range out of range", path: "Items[2:4]", wantErr: true},
+		{name: "inverted range", path: "Items[2:1]", wantErr: true},
+		{name: "unloaded", path: "Deep", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolvePath(v, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolvePath() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Value != tt.want {
+				t.Errorf("resolvePath() = %q, want %q", got.Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchesType(t *testing.T) {
+	tests := []struct {
+		typ  string
+		t    string
+		want bool
+	}{
+		{typ: "main.Point", t: "main.Point", want: true},
+		{typ: "github.com/google/uuid.UUID", t: "uuid.UUID", want: true},
+		{typ: "github.com/google/uuid.UUID", t: "github.com/google/uuid.UUID", want: true},
+		{typ: "[]github.com/google/uuid.UUID", t: "uuid.UUID", want: false},
+		{typ: "map[string]github.com/google/uuid.UUID", t: "uuid.UUID", want: false},
+		{typ: "*github.com/google/uuid.UUID", t: "uuid.UUID", want: false},
+		{typ: "github.com/other/myuuid.UUID", t: "uuid.UUID", want: false},
+		{typ: "main.Point", t: "Point", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ+" "+tt.t, func(t *testing.T) {
+			if got := matchesType(tt.typ, tt.t); got != tt.want {
+				t.Errorf("matchesType(%q, %q) = %t, want %t", tt.typ, tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrinterFor(t *testing.T) {
+	d := Debugger{printers: []config.Printer{
+		{Type: "uuid.UUID", Template: "user"},
+		{Type: "main.Point", Template: "user"},
+		{Type: "uuid.UUID", Template: "project"},
+	}}
+
+	tests := []struct {
+		typ    string
+		want   string
+		wantOK bool
+	}{
+		{typ: "github.com/google/uuid.UUID", want: "project", wantOK: true},
+		{typ: "main.Point", want: "user", wantOK: true},
+		{typ: "[]main.Point", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			got, ok := d.printerFor(tt.typ)
+			if ok != tt.wantOK || got.Template != tt.want {
+				t.Errorf("printerFor(%q) = %q, %t, want %q, %t", tt.typ, got.Template, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
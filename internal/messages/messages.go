@@ -1,6 +1,9 @@
 package messages
 
-import "github.com/charmbracelet/bubbletea"
+import (
+	"github.com/andersonjoseph/drill/internal/debugger"
+	"github.com/charmbracelet/bubbletea"
+)
 
 type Error error
 
@@ -24,6 +27,12 @@ type DebuggerBreakpointToggled struct {
 	Line     int
 }
 
+type DebuggerBreakpointAmended struct {
+	ID       int
+	Filename string
+	Line     int
+}
+
 type DebuggerBreakpointCleared struct {
 	ID       int
 	Filename string
@@ -37,6 +46,8 @@ type DebuggerBreakpointSelected struct {
 	FromWindowID int
 }
 
+type DebuggerBreakpointHit debugger.BreakpointHit
+
 type DebuggerStdoutReceived string
 type DebuggerStderrReceived string
 type DebuggerCommandReceived string
@@ -53,6 +64,13 @@ type WindowTitleChanged struct {
 
 type UpdatedHint string
 
+// WatchExpressionAdded pins an expression to the Watch window.
+type WatchExpressionAdded string
+
+// CommandRequested opens the command input with the given text, ready to be
+// edited and run.
+type CommandRequested string
+
 func DebuggerBreakpointClearedCmd(id int, file string, line int) tea.Cmd {
 	return func() tea.Msg {
 		return DebuggerBreakpointCleared{ID: id, Line: line, Filename: file}
@@ -65,6 +83,12 @@ func DebuggerBreakpointToggledCmd(id int, file string, line int) tea.Cmd {
 	}
 }
 
+func DebuggerBreakpointAmendedCmd(id int, file string, line int) tea.Cmd {
+	return func() tea.Msg {
+		return DebuggerBreakpointAmended{ID: id, Line: line, Filename: file}
+	}
+}
+
 func DebuggerBreakpointCreatedCmd(id int, file string, line int) tea.Cmd {
 	return func() tea.Msg {
 		return DebuggerBreakpointCreated{ID: id, Line: line, Filename: file}
@@ -77,6 +101,24 @@ func DebuggerBreakpointSelectedCmd(id int, file string, line int, fromWindowID i
 	}
 }
 
+func DebuggerBreakpointHitCmd(hit debugger.BreakpointHit) tea.Cmd {
+	return func() tea.Msg {
+		return DebuggerBreakpointHit(hit)
+	}
+}
+
+// DebuggerSteppedCmd reports a stop, followed by the breakpoints hit on the
+// way so logpoints, captures, temporary breakpoints and dependencies are
+// handled however the program got there.
+func DebuggerSteppedCmd(hits []debugger.BreakpointHit) tea.Cmd {
+	cmds := []tea.Cmd{func() tea.Msg { return DebuggerStepped{} }}
+	for _, hit := range hits {
+		cmds = append(cmds, DebuggerBreakpointHitCmd(hit))
+	}
+
+	return tea.Sequence(cmds...)
+}
+
 func ErrorCmd(err error) tea.Cmd {
 	return func() tea.Msg {
 		if err == nil {
@@ -85,3 +127,21 @@ func ErrorCmd(err error) tea.Cmd {
 		return Error(err)
 	}
 }
+
+func WatchExpressionAddedCmd(expr string) tea.Cmd {
+	return func() tea.Msg {
+		return WatchExpressionAdded(expr)
+	}
+}
+
+// OutputCmd writes content to the Output window, as if it was printed by a
+// command.
+func OutputCmd(output chan<- debugger.Output, content string) tea.Cmd {
+	return func() tea.Msg {
+		output <- debugger.Output{
+			Source:  debugger.SourceCommand,
+			Content: content,
+		}
+		return nil
+	}
+}
@@ -4,6 +4,20 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+)
+
+type Origin int
+
+const (
+	OriginUser Origin = iota
+	OriginDependency
+	OriginStdlib
+)
+
+var (
+	goRoot   = sync.OnceValue(func() string { return goEnv("GOROOT") })
+	modCache = sync.OnceValue(func() string { return goEnv("GOMODCACHE") })
 )
 
 func GetProjectRoot() string {
@@ -18,6 +32,46 @@ func GetProjectRoot() string {
 	return filepath.Dir(strings.TrimSpace(string(goModPath)))
 }
 
+// Classify tells whether filename belongs to the user's code, to a module
+// dependency or to the Go standard library (runtime included).
+func Classify(filename string) Origin {
+	if filename == "" || filename == "<autogenerated>" {
+		return OriginStdlib
+	}
+
+	if root := goRoot(); root != "" && isInDir(filename, root) {
+		return OriginStdlib
+	}
+
+	if cache := modCache(); cache != "" && isInDir(filename, cache) {
+		return OriginDependency
+	}
+
+	if strings.Contains(filepath.ToSlash(filename), "/vendor/") {
+		return OriginDependency
+	}
+
+	return OriginUser
+}
+
+func isInDir(filename, dir string) bool {
+	rel, err := filepath.Rel(dir, filename)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func goEnv(key string) string {
+	out, err := exec.Command("go", "env", key).Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
 func Trunc(path string, maxWidth int) string {
 	if len(path) <= maxWidth {
 		return path
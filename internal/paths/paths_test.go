@@ -0,0 +1,46 @@
+package paths
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	root, cache := goRoot(), modCache()
+	if root == "" || cache == "" {
+		t.Skip("go env is not available")
+	}
+
+	check := func(filename string, want Origin) {
+		t.Helper()
+		if got := Classify(filename); got != want {
+			t.Errorf("Classify(%q) = %d, want %d", filename, got, want)
+		}
+	}
+
+	check(filepath.Join(root, "src", "runtime", "proc.go"), OriginStdlib)
+	check(filepath.Join(root, "src", "fmt", "print.go"), OriginStdlib)
+	check("", OriginStdlib)
+	check("<autogenerated>", OriginStdlib)
+
+	check(filepath.Join(cache, "github.com", "charmbracelet", "bubbletea@v1.3.4", "tea.go"), OriginDependency)
+	check(filepath.Join("/src", "project", "vendor", "github.com", "pkg", "errors", "errors.go"), OriginDependency)
+
+	check(filepath.Join("/src", "project", "main.go"), OriginUser)
+	// a directory next to GOROOT sharing its name as a prefix is not in it
+	check(root+"-project"+string(filepath.Separator)+"main.go", OriginUser)
+}
+
+func TestIsInDir(t *testing.T) {
+	dir := filepath.Join("/usr", "local", "go")
+
+	if !isInDir(filepath.Join(dir, "src", "fmt", "print.go"), dir) {
+		t.Errorf("a file under %s should be in it", dir)
+	}
+	if isInDir(filepath.Join("/usr", "local", "gopher", "main.go"), dir) {
+		t.Errorf("a file in a sibling directory should not be in %s", dir)
+	}
+	if isInDir(filepath.Join("/usr", "local", "main.go"), dir) {
+		t.Errorf("a file in the parent directory should not be in %s", dir)
+	}
+}
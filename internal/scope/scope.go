@@ -0,0 +1,387 @@
+// Package scope finds the names visible at a source line by reading the
+// source, so expressions can be checked without stopping the program there.
+package scope
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// VisibleNames returns the variables and constants an expression evaluated at
+// filename:line can reference, the innermost ones first.
+func VisibleNames(filename string, line int) ([]string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", filename, err)
+	}
+
+	names := localNames(fset, file, line)
+	slices.Reverse(names)
+
+	pkgNames, err := packageNames(filename, file.Name.Name, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return dedup(append(names, pkgNames...)), nil
+}
+
+// Undefined parses expr and returns the identifiers it uses that are not
+// visible at filename:line.
+func Undefined(expr string, filename string, line int) ([]string, error) {
+	e, err := parser.ParseExpr(expr)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing expression: %w", err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", filename, err)
+	}
+
+	pkgNames, err := packageNames(filename, file.Name.Name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make(map[string]bool)
+	for _, name := range append(localNames(fset, file, line), pkgNames...) {
+		visible[name] = true
+	}
+
+	var undefined []string
+	for _, ident := range referencedIdents(e) {
+		if visible[ident] || types.Universe.Lookup(ident) != nil || slices.Contains(undefined, ident) {
+			continue
+		}
+		undefined = append(undefined, ident)
+	}
+
+	return undefined, nil
+}
+
+// LineIdentifiers returns the variables used on line, in the order they
+// appear.
+func LineIdentifiers(filename string, line int) ([]string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", filename, err)
+	}
+
+	pkgNames, err := packageNames(filename, file.Name.Name, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// names declared on the line are visible right after it
+	visible := make(map[string]bool)
+	for _, name := range append(localNames(fset, file, line+1), pkgNames...) {
+		visible[name] = true
+	}
+
+	var idents []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil || fset.Position(n.Pos()).Line > line || fset.Position(n.End()).Line < line {
+			return false
+		}
+
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			ast.Inspect(n.X, func(n ast.Node) bool {
+				if ident, ok := n.(*ast.Ident); ok && fset.Position(ident.Pos()).Line == line && visible[ident.Name] {
+					idents = append(idents, ident.Name)
+				}
+				return true
+			})
+			return false
+
+		case *ast.Ident:
+			if fset.Position(n.Pos()).Line == line && visible[n.Name] {
+				idents = append(idents, n.Name)
+			}
+		}
+
+		return true
+	})
+
+	return dedup(idents), nil
+}
+
+// FunctionEnd returns the last line of the innermost function, declared or
+// literal, enclosing line. ok is false when line is outside every function.
+func FunctionEnd(filename string, line int) (end int, ok bool, err error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return 0, false, fmt.Errorf("error parsing %s: %w", filename, err)
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil || fset.Position(n.Pos()).Line > line || fset.Position(n.End()).Line < line {
+			return false
+		}
+
+		switch n.(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			end, ok = fset.Position(n.End()).Line, true
+		}
+
+		return true
+	})
+
+	return end, ok, nil
+}
+
+// localNames returns the receiver, parameters, results and locals declared
+// before line in the functions and blocks enclosing it, outermost first.
+func localNames(fset *token.FileSet, file *ast.File, line int) []string {
+	var names []string
+
+	contains := func(n ast.Node) bool {
+		return fset.Position(n.Pos()).Line <= line && fset.Position(n.End()).Line >= line
+	}
+	before := func(n ast.Node) bool {
+		return fset.Position(n.Pos()).Line < line
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n == nil || !contains(n) {
+			return false
+		}
+
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			names = append(names, fieldNames(n.Recv)...)
+			names = append(names, fieldNames(n.Type.Params)...)
+			names = append(names, fieldNames(n.Type.Results)...)
+
+		case *ast.FuncLit:
+			names = append(names, fieldNames(n.Type.Params)...)
+			names = append(names, fieldNames(n.Type.Results)...)
+
+		case *ast.BlockStmt:
+			for _, stmt := range n.List {
+				if before(stmt) && !contains(stmt) {
+					names = append(names, declaredNames(stmt)...)
+				}
+			}
+
+		case *ast.CaseClause:
+			for _, stmt := range n.Body {
+				if before(stmt) && !contains(stmt) {
+					names = append(names, declaredNames(stmt)...)
+				}
+			}
+
+		case *ast.CommClause:
+			if n.Comm != nil {
+				names = append(names, declaredNames(n.Comm)...)
+			}
+			for _, stmt := range n.Body {
+				if before(stmt) && !contains(stmt) {
+					names = append(names, declaredNames(stmt)...)
+				}
+			}
+
+		case *ast.IfStmt:
+			if n.Init != nil {
+				names = append(names, declaredNames(n.Init)...)
+			}
+
+		case *ast.ForStmt:
+			if n.Init != nil {
+				names = append(names, declaredNames(n.Init)...)
+			}
+
+		case *ast.SwitchStmt:
+			if n.Init != nil {
+				names = append(names, declaredNames(n.Init)...)
+			}
+
+		case *ast.TypeSwitchStmt:
+			if n.Init != nil {
+				names = append(names, declaredNames(n.Init)...)
+			}
+			names = append(names, declaredNames(n.Assign)...)
+
+		case *ast.RangeStmt:
+			if n.Tok == token.DEFINE {
+				names = append(names, exprNames(n.Key, n.Value)...)
+			}
+		}
+
+		return true
+	})
+
+	return names
+}
+
+func declaredNames(stmt ast.Stmt) []string {
+	switch stmt := stmt.(type) {
+	case *ast.AssignStmt:
+		if stmt.Tok == token.DEFINE {
+			return exprNames(stmt.Lhs...)
+		}
+
+	case *ast.DeclStmt:
+		return genDeclNames(stmt.Decl, false)
+
+	case *ast.LabeledStmt:
+		return declaredNames(stmt.Stmt)
+	}
+
+	return nil
+}
+
+// packageNames returns the package level names declared in the package of
+// filename. Functions, types and imports are only included when all is set.
+func packageNames(filename string, pkg string, all bool) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(filename), "*.go"))
+	if err != nil {
+		return nil, fmt.Errorf("error listing package files: %w", err)
+	}
+
+	var names []string
+	fset := token.NewFileSet()
+	for _, match := range matches {
+		file, err := parser.ParseFile(fset, match, nil, parser.SkipObjectResolution)
+		if err != nil || file.Name.Name != pkg {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok {
+				if all && fn.Recv == nil {
+					names = append(names, fn.Name.Name)
+				}
+				continue
+			}
+			names = append(names, genDeclNames(decl, all)...)
+		}
+
+		if all && match == filename {
+			names = append(names, importNames(file)...)
+		}
+	}
+
+	return names, nil
+}
+
+func genDeclNames(decl ast.Decl, all bool) []string {
+	gen, ok := decl.(*ast.GenDecl)
+	if !ok {
+		return nil
+	}
+
+	var names []string
+	for _, spec := range gen.Specs {
+		switch spec := spec.(type) {
+		case *ast.ValueSpec:
+			for _, name := range spec.Names {
+				names = append(names, name.Name)
+			}
+		case *ast.TypeSpec:
+			if all {
+				names = append(names, spec.Name.Name)
+			}
+		}
+	}
+
+	return names
+}
+
+func importNames(file *ast.File) []string {
+	names := make([]string, 0, len(file.Imports))
+	for _, imp := range file.Imports {
+		if imp.Name != nil {
+			names = append(names, imp.Name.Name)
+			continue
+		}
+
+		path := strings.Trim(imp.Path.Value, `"`)
+		names = append(names, path[strings.LastIndex(path, "/")+1:])
+	}
+
+	return names
+}
+
+// referencedIdents returns the identifiers expr looks up in its scope. The
+// leftmost part of a selector is skipped when it could be a package name,
+// since Delve can reference packages the file doesn't import.
+func referencedIdents(expr ast.Expr) []string {
+	var idents []string
+
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			if _, ok := n.X.(*ast.Ident); ok {
+				return false
+			}
+			idents = append(idents, referencedIdents(n.X)...)
+			return false
+
+		case *ast.KeyValueExpr:
+			idents = append(idents, referencedIdents(n.Value)...)
+			return false
+
+		case *ast.Ident:
+			if n.Name != "_" {
+				idents = append(idents, n.Name)
+			}
+		}
+
+		return true
+	})
+
+	return idents
+}
+
+func fieldNames(fields *ast.FieldList) []string {
+	if fields == nil {
+		return nil
+	}
+
+	var names []string
+	for _, field := range fields.List {
+		for _, name := range field.Names {
+			if name.Name != "_" {
+				names = append(names, name.Name)
+			}
+		}
+	}
+
+	return names
+}
+
+func exprNames(exprs ...ast.Expr) []string {
+	var names []string
+	for _, expr := range exprs {
+		if ident, ok := expr.(*ast.Ident); ok && ident.Name != "_" {
+			names = append(names, ident.Name)
+		}
+	}
+
+	return names
+}
+
+func dedup(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	unique := names[:0]
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+
+	return unique
+}
@@ -0,0 +1,176 @@
+package scope
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const sample = `package sample
+
+import (
+	"fmt"
+	str "strings"
+)
+
+const limit = 10
+
+var counter int
+
+type point struct{ x, y int }
+
+func (p *point) scale(factor int) (result int) {
+	before := p.x // @before
+	if n := factor * 2; n > limit {
+		inner := n // @if
+		_ = inner
+	}
+	after := before // @after
+	for i, v := range []int{1, 2} {
+		_ = i + v // @range
+	}
+	fn := func(arg string) {
+		_ = arg // @closure
+	} // @closure-end
+	fn(fmt.Sprint(after)) // @call
+	return str.Count("", "")
+} // @scale-end
+`
+
+// writeSample writes the sample package, with a second file and one of
+// another package next to it, and returns the path of its main file.
+func writeSample(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"sample.go": sample,
+		"other.go":  "package sample\n\nvar shared = 1\n\nfunc helper() {}\n",
+		"skip.go":   "package other\n\nvar hidden = 1\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return filepath.Join(dir, "sample.go")
+}
+
+// lineOf returns the line of the sample marked with "// @marker".
+func lineOf(t *testing.T, marker string) int {
+	t.Helper()
+
+	for i, line := range strings.Split(sample, "\n") {
+		if strings.HasSuffix(line, "// @"+marker) {
+			return i + 1
+		}
+	}
+
+	t.Fatalf("no line marked %s", marker)
+	return 0
+}
+
+func TestVisibleNames(t *testing.T) {
+	filename := writeSample(t)
+
+	tests := map[string][]string{
+		"if":      {"n", "before", "result", "factor", "p", "shared", "limit", "counter"},
+		"range":   {"v", "i", "after", "before", "result", "factor", "p", "shared", "limit", "counter"},
+		"closure": {"arg", "after", "before", "result", "factor", "p", "shared", "limit", "counter"},
+	}
+
+	for marker, want := range tests {
+		got, err := VisibleNames(filename, lineOf(t, marker))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("VisibleNames() at @%s = %v, want %v", marker, got, want)
+		}
+	}
+}
+
+func TestUndefined(t *testing.T) {
+	filename := writeSample(t)
+	line := lineOf(t, "if")
+
+	defined := []string{
+		"n > limit && counter == 0",
+		"len(p.x) > 0",
+		"fmt.Sprint(n) != \"\"",
+		// Delve can reference packages the file doesn't import
+		"runtime.curg.goid == 1",
+		"point{x: n}.x == before",
+		"helper != nil && shared > 0",
+		"result == factor",
+		"str.Count(\"\", \"\") == 0",
+	}
+	for _, expr := range defined {
+		undefined, err := Undefined(expr, filename, line)
+		if err != nil {
+			t.Errorf("Undefined(%q) error = %v", expr, err)
+		}
+		if len(undefined) > 0 {
+			t.Errorf("Undefined(%q) = %v, want none", expr, undefined)
+		}
+	}
+
+	undefined, err := Undefined("inner > after && hidden == missing", filename, line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"inner", "after", "hidden", "missing"}; !slices.Equal(undefined, want) {
+		t.Errorf("Undefined() = %v, want %v", undefined, want)
+	}
+
+	if _, err := Undefined("n >", filename, line); err == nil {
+		t.Error("Undefined() of an invalid expression should fail")
+	}
+}
+
+func TestLineIdentifiers(t *testing.T) {
+	filename := writeSample(t)
+
+	tests := map[string][]string{
+		"before": {"before", "p"},
+		"after":  {"after", "before"},
+		"range":  {"i", "v"},
+		"call":   {"fn", "after"},
+	}
+
+	for marker, want := range tests {
+		got, err := LineIdentifiers(filename, lineOf(t, marker))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("LineIdentifiers() at @%s = %v, want %v", marker, got, want)
+		}
+	}
+}
+
+func TestFunctionEnd(t *testing.T) {
+	filename := writeSample(t)
+
+	check := func(line int, want int, wantOK bool) {
+		t.Helper()
+		end, ok, err := FunctionEnd(filename, line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if end != want || ok != wantOK {
+			t.Errorf("FunctionEnd(%d) = %d, %t, want %d, %t", line, end, ok, want, wantOK)
+		}
+	}
+
+	check(lineOf(t, "if"), lineOf(t, "scale-end"), true)
+	check(lineOf(t, "call"), lineOf(t, "scale-end"), true)
+	// the innermost function, the literal, ends first
+	check(lineOf(t, "closure"), lineOf(t, "closure-end"), true)
+	// package level declarations are in no function
+	check(1, 0, false)
+	check(lineOf(t, "scale-end")+1, 0, false)
+}
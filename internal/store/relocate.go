@@ -0,0 +1,106 @@
+package store
+
+import (
+	"os"
+	"strings"
+)
+
+const fingerprintRadius = 3
+
+// Fingerprint identifies a source line by its content and the lines around
+// it, so a breakpoint can follow its line when the file is edited.
+type Fingerprint struct {
+	Before []string `json:"before,omitempty"`
+	Line   string   `json:"line"`
+	After  []string `json:"after,omitempty"`
+}
+
+func ReadLines(filename string) ([]string, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Split(string(content), "\n"), nil
+}
+
+func NewFingerprint(lines []string, line int) *Fingerprint {
+	if line < 1 || line > len(lines) {
+		return nil
+	}
+
+	i := line - 1
+	start := max(0, i-fingerprintRadius)
+	end := min(len(lines), i+fingerprintRadius+1)
+
+	return &Fingerprint{
+		Before: trimLines(lines[start:i]),
+		Line:   strings.TrimSpace(lines[i]),
+		After:  trimLines(lines[i+1 : end]),
+	}
+}
+
+// Relocate finds the line bp points to in the current version of the file.
+// It returns false when the line can't be found with enough confidence.
+func Relocate(lines []string, bp Breakpoint) (int, bool) {
+	f := bp.Fingerprint
+	if f == nil {
+		return bp.Line, bp.Line >= 1 && bp.Line <= len(lines)
+	}
+
+	bestLine, bestScore := 0, 0
+	for i := range lines {
+		if strings.TrimSpace(lines[i]) != f.Line {
+			continue
+		}
+
+		score := f.score(lines, i)
+		if score > bestScore || (score == bestScore && distance(i+1, bp.Line) < distance(bestLine, bp.Line)) {
+			bestLine, bestScore = i+1, score
+		}
+	}
+
+	// the line itself plus at least half of its surroundings have to match
+	minScore := 1 + (len(f.Before)+len(f.After)+1)/2
+	if bestLine == 0 || bestScore < minScore {
+		return 0, false
+	}
+
+	return bestLine, true
+}
+
+func (f Fingerprint) score(lines []string, i int) int {
+	score := 1
+
+	for j, before := range f.Before {
+		k := i - len(f.Before) + j
+		if k >= 0 && strings.TrimSpace(lines[k]) == before {
+			score++
+		}
+	}
+
+	for j, after := range f.After {
+		k := i + 1 + j
+		if k < len(lines) && strings.TrimSpace(lines[k]) == after {
+			score++
+		}
+	}
+
+	return score
+}
+
+func trimLines(lines []string) []string {
+	trimmed := make([]string, len(lines))
+	for i := range lines {
+		trimmed[i] = strings.TrimSpace(lines[i])
+	}
+
+	return trimmed
+}
+
+func distance(a, b int) int {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
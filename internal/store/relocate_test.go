@@ -0,0 +1,126 @@
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+const source = `package main
+
+func main() {
+	x := 1
+	y := 2
+	fmt.Println(x + y)
+	x++
+	fmt.Println(x + y)
+}`
+
+// saved returns the breakpoint a previous session saved at line of source.
+func saved(line int) Breakpoint {
+	lines := strings.Split(source, "\n")
+	return Breakpoint{Line: line, Fingerprint: NewFingerprint(lines, line)}
+}
+
+func TestRelocateFollowsEdits(t *testing.T) {
+	lines := strings.Split(source, "\n")
+
+	assertLine := func(t *testing.T, lines []string, bp Breakpoint, want int) {
+		t.Helper()
+		got, ok := Relocate(lines, bp)
+		if !ok || got != want {
+			t.Errorf("Relocate() = %d, %t, want %d, true", got, ok, want)
+		}
+	}
+
+	t.Run("unchanged file", func(t *testing.T) {
+		assertLine(t, lines, saved(5), 5)
+	})
+
+	t.Run("lines inserted before", func(t *testing.T) {
+		edited := insertLines(lines, 3, "\t// setup", "\tz := 0", "\t_ = z")
+		assertLine(t, edited, saved(5), 8)
+	})
+
+	t.Run("line deleted before", func(t *testing.T) {
+		assertLine(t, deleteLine(lines, 4), saved(6), 5)
+	})
+
+	t.Run("reindented", func(t *testing.T) {
+		edited := make([]string, len(lines))
+		for i := range lines {
+			edited[i] = strings.ReplaceAll(lines[i], "\t", "    ")
+		}
+		assertLine(t, edited, saved(5), 5)
+	})
+
+	t.Run("repeated line told apart by its surroundings", func(t *testing.T) {
+		assertLine(t, lines, saved(6), 6)
+		assertLine(t, lines, saved(8), 8)
+	})
+
+	t.Run("repeated line with equal surroundings keeps the closest", func(t *testing.T) {
+		repeated := strings.Split("a\nb\nc\nx\n\nx\nb\nc", "\n")
+		bp := Breakpoint{Line: 6, Fingerprint: &Fingerprint{Line: "x"}}
+		assertLine(t, repeated, bp, 6)
+	})
+}
+
+func TestRelocateGivesUp(t *testing.T) {
+	lines := strings.Split(source, "\n")
+
+	if line, ok := Relocate(deleteLine(lines, 5), saved(5)); ok {
+		t.Errorf("the line was removed, Relocate() = %d, want it not found", line)
+	}
+
+	rewritten := strings.Split("package other\n\nfunc f() {\n\ta()\n\ty := 2\n\tb()\n}", "\n")
+	if line, ok := Relocate(rewritten, saved(5)); ok {
+		t.Errorf("the line is in another context now, Relocate() = %d, want it not found", line)
+	}
+}
+
+func TestRelocateWithoutFingerprint(t *testing.T) {
+	lines := strings.Split(source, "\n")
+
+	if line, ok := Relocate(lines, Breakpoint{Line: 4}); !ok || line != 4 {
+		t.Errorf("Relocate() = %d, %t, want the saved line 4", line, ok)
+	}
+	if _, ok := Relocate(lines, Breakpoint{Line: 42}); ok {
+		t.Error("a line past the end of the file should not be found")
+	}
+}
+
+func TestFingerprintScore(t *testing.T) {
+	lines := strings.Split(source, "\n")
+
+	tests := []struct {
+		name        string
+		fingerprint *Fingerprint
+		i           int
+		want        int
+	}{
+		{name: "every line matches", fingerprint: NewFingerprint(lines, 5), i: 4, want: 7},
+		{name: "only the line matches", fingerprint: &Fingerprint{Before: []string{"a", "b"}, Line: "y := 2", After: []string{"c"}}, i: 4, want: 1},
+		{name: "surroundings cut by the start of the file", fingerprint: NewFingerprint(lines, 1), i: 0, want: 4},
+		{name: "surroundings cut by the end of the file", fingerprint: NewFingerprint(lines, 9), i: 8, want: 4},
+		{name: "surroundings past the end of the file", fingerprint: &Fingerprint{Line: "}", After: []string{"", "func other() {"}}, i: 8, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fingerprint.score(lines, tt.i); got != tt.want {
+				t.Errorf("score() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func insertLines(lines []string, after int, inserted ...string) []string {
+	result := append([]string{}, lines[:after]...)
+	result = append(result, inserted...)
+	return append(result, lines[after:]...)
+}
+
+func deleteLine(lines []string, line int) []string {
+	result := append([]string{}, lines[:line-1]...)
+	return append(result, lines[line:]...)
+}
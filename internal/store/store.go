@@ -0,0 +1,123 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Breakpoint struct {
+	Filename     string `json:"filename"`
+	Line         int    `json:"line"`
+	Name         string `json:"name,omitempty"`
+	Condition    string `json:"condition,omitempty"`
+	HitCondition string `json:"hitCondition,omitempty"`
+	HitCondPerG  bool   `json:"hitCondPerG,omitempty"`
+	Disabled     bool   `json:"disabled,omitempty"`
+	LogMessage   string `json:"logMessage,omitempty"`
+	Group        string `json:"group,omitempty"`
+	Temporary    bool   `json:"temporary,omitempty"`
+	CaptureOnHit bool   `json:"captureOnHit,omitempty"`
+	// DependsOn is the filename:line of the breakpoint this one waits for.
+	DependsOn string `json:"dependsOn,omitempty"`
+
+	Fingerprint *Fingerprint `json:"fingerprint,omitempty"`
+	// Orphaned is set when the breakpoint's line could not be found anymore.
+	Orphaned bool `json:"orphaned,omitempty"`
+}
+
+// Project is everything drill remembers about a project between sessions.
+type Project struct {
+	Breakpoints []Breakpoint `json:"breakpoints"`
+	Watches     []string     `json:"watches,omitempty"`
+}
+
+func Load(projectRoot string) (Project, error) {
+	var p Project
+
+	path, err := projectPath(projectRoot)
+	if err != nil {
+		return p, fmt.Errorf("error loading project state: %w", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return p, fmt.Errorf("error loading project state: %w", err)
+	}
+
+	if err := json.Unmarshal(content, &p); err != nil {
+		return p, fmt.Errorf("error loading project state: %s: %w", path, err)
+	}
+
+	return p, nil
+}
+
+func Save(projectRoot string, p Project) error {
+	path, err := projectPath(projectRoot)
+	if err != nil {
+		return fmt.Errorf("error saving project state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error saving project state: %w", err)
+	}
+
+	content, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error saving project state: %w", err)
+	}
+
+	// write to a temporary file first so a crash never leaves a truncated state behind
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o644); err != nil {
+		return fmt.Errorf("error saving project state: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("error saving project state: %w", err)
+	}
+
+	return nil
+}
+
+// Update loads the project state, applies fn to it and saves it back.
+func Update(projectRoot string, fn func(*Project)) error {
+	p, err := Load(projectRoot)
+	if err != nil {
+		return err
+	}
+
+	fn(&p)
+
+	return Save(projectRoot, p)
+}
+
+func projectPath(projectRoot string) (string, error) {
+	if projectRoot == "" {
+		return "", errors.New("unknown project root")
+	}
+
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("error finding state directory: %w", err)
+		}
+		stateDir = filepath.Join(home, ".local", "state")
+	}
+
+	name := strings.Trim(strings.ReplaceAll(filepath.ToSlash(projectRoot), "/", "%"), "%")
+
+	return filepath.Join(stateDir, "drill", "projects", name+".json"), nil
+}
+
+// Location identifies a breakpoint by its filename and line.
+func Location(filename string, line int) string {
+	return fmt.Sprintf("%s:%d", filename, line)
+}